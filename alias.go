@@ -0,0 +1,11 @@
+//go:build go1.22
+
+package sqlmap
+
+import "go/types"
+
+//unalias return the type denoted by the alias, e.g. interface{} of any which is an alias since go1.23,
+//typ itself if it is not an alias
+func unalias(typ types.Type) types.Type {
+	return types.Unalias(typ)
+}
@@ -0,0 +1,10 @@
+//go:build !go1.22
+
+package sqlmap
+
+import "go/types"
+
+//unalias return typ itself, the aliases are not represented by the types before go1.22
+func unalias(typ types.Type) types.Type {
+	return typ
+}
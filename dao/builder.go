@@ -0,0 +1,138 @@
+package dao
+
+import (
+	"strconv"
+	"strings"
+)
+
+//the bindvars of the dialects
+const (
+	BindQuestion = "?"
+	BindDollar   = "$"
+)
+
+//the clauses of the dynamic query which are omitted if they are empty
+const (
+	ClauseWhere = "WHERE"
+	ClauseSet   = "SET"
+)
+
+//SQLBuilder build the sql and the args of a dynamic query at runtime, e.g.
+//    b := NewSQLBuilder(BindQuestion)
+//    b.Write("SELECT id FROM user ")
+//    b.Begin(ClauseWhere)
+//    if len(name) > 0 {
+//        b.Write(" AND name = ")
+//        b.Arg(name)
+//    }
+//    b.End()
+type SQLBuilder struct {
+	bindVar string
+	buf     []byte
+	args    []any
+	clauses []sqlClause
+}
+
+type sqlClause struct {
+	keyword string
+	start   int
+}
+
+//NewSQLBuilder return the builder of the bindvar, BindQuestion or BindDollar
+func NewSQLBuilder(bindVar string) *SQLBuilder {
+	return &SQLBuilder{bindVar: bindVar, buf: make([]byte, 0, 256)}
+}
+
+//Write append the sql
+func (b *SQLBuilder) Write(sql string) {
+	b.buf = append(b.buf, sql...)
+}
+
+//Arg append the bindvar of the arg, the dollar bindvars are numbered in the order they are appended
+func (b *SQLBuilder) Arg(arg any) {
+	b.args = append(b.args, arg)
+	b.buf = append(b.buf, b.bindVar...)
+	if b.bindVar == BindDollar {
+		b.buf = strconv.AppendInt(b.buf, int64(len(b.args)), 10)
+	}
+}
+
+//In append the bindvars of the items separated by comma for the IN clause, e.g. id IN (?, ?, ?),
+//NULL is appended if items is empty, then IN (NULL) matches no rows
+func In[T any](b *SQLBuilder, items []T) {
+	if len(items) == 0 {
+		b.Write("NULL")
+		return
+	}
+	for i, item := range items {
+		if i > 0 {
+			b.Write(", ")
+		}
+		b.Arg(item)
+	}
+}
+
+//NotIn append the NOT IN predicate of the operand and the items, e.g. id NOT IN (?, ?, ?),
+//1=1 is appended if items is empty, it matches every row as NOT IN an empty set,
+//but NOT IN (NULL) matches no rows
+func NotIn[T any](b *SQLBuilder, operand string, items []T) {
+	if len(items) == 0 {
+		b.Write("1=1")
+		return
+	}
+	b.Write(operand)
+	b.Write(" NOT IN (")
+	In(b, items)
+	b.Write(")")
+}
+
+//Begin begin the clause of the keyword, ClauseWhere or ClauseSet, it is closed by End
+func (b *SQLBuilder) Begin(keyword string) {
+	b.clauses = append(b.clauses, sqlClause{keyword: keyword, start: len(b.buf)})
+}
+
+//End close the clause begun last, the clause is omitted if it is empty,
+//otherwise the leading AND or OR of WHERE and the trailing comma of SET are trimmed
+func (b *SQLBuilder) End() {
+	if len(b.clauses) == 0 {
+		return
+	}
+	clause := b.clauses[len(b.clauses)-1]
+	b.clauses = b.clauses[:len(b.clauses)-1]
+
+	content := strings.TrimSpace(string(b.buf[clause.start:]))
+	switch clause.keyword {
+	case ClauseWhere:
+		content = trimWord(trimWord(content, "AND"), "OR")
+	case ClauseSet:
+		content = strings.TrimSpace(strings.TrimSuffix(content, ","))
+	}
+	b.buf = b.buf[:clause.start]
+	if len(content) > 0 {
+		b.buf = append(b.buf, clause.keyword...)
+		b.buf = append(b.buf, ' ')
+		b.buf = append(b.buf, content...)
+		b.buf = append(b.buf, ' ')
+	}
+}
+
+//SQL return the built sql
+func (b *SQLBuilder) SQL() string {
+	return strings.TrimSpace(string(b.buf))
+}
+
+//Args return the args in the order of the bindvars
+func (b *SQLBuilder) Args() []any {
+	return b.args
+}
+
+//trimWord trim the leading word of the sql case-insensitively
+func trimWord(sql, word string) string {
+	if len(sql) <= len(word) || !strings.EqualFold(sql[:len(word)], word) {
+		return sql
+	}
+	if c := sql[len(word)]; c != ' ' && c != '\t' && c != '\n' && c != '\r' && c != '(' {
+		return sql
+	}
+	return strings.TrimSpace(sql[len(word):])
+}
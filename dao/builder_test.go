@@ -0,0 +1,110 @@
+package dao
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSQLBuilder(t *testing.T) {
+	tests := []struct {
+		name     string
+		bindVar  string
+		build    func(b *SQLBuilder)
+		wantSQL  string
+		wantArgs []any
+	}{
+		{
+			name:    "Where",
+			bindVar: BindQuestion,
+			build: func(b *SQLBuilder) {
+				b.Write("SELECT id FROM user ")
+				b.Begin(ClauseWhere)
+				b.Write(" AND name = ")
+				b.Arg("Lucy")
+				b.Write(" OR (gender = ")
+				b.Arg(2)
+				b.Write(")")
+				b.End()
+				b.Write("ORDER BY id")
+			},
+			wantSQL:  "SELECT id FROM user WHERE name = ? OR (gender = ?) ORDER BY id",
+			wantArgs: []any{"Lucy", 2},
+		},
+		{
+			name:    "Empty Where",
+			bindVar: BindQuestion,
+			build: func(b *SQLBuilder) {
+				b.Write("SELECT id FROM user ")
+				b.Begin(ClauseWhere)
+				b.Write("  ")
+				b.End()
+				b.Write("ORDER BY id")
+			},
+			wantSQL: "SELECT id FROM user ORDER BY id",
+		},
+		{
+			name:    "Set",
+			bindVar: BindDollar,
+			build: func(b *SQLBuilder) {
+				b.Write("UPDATE user ")
+				b.Begin(ClauseSet)
+				b.Write("name = ")
+				b.Arg("Lily")
+				b.Write(", ")
+				b.End()
+				b.Write("WHERE id = ")
+				b.Arg(1)
+			},
+			wantSQL:  "UPDATE user SET name = $1 WHERE id = $2",
+			wantArgs: []any{"Lily", 1},
+		},
+		{
+			name:    "In",
+			bindVar: BindDollar,
+			build: func(b *SQLBuilder) {
+				b.Write("SELECT id FROM user WHERE id IN (")
+				In(b, []int64{1, 2})
+				b.Write(") AND gender IN (")
+				In(b, []int{})
+				b.Write(")")
+			},
+			wantSQL:  "SELECT id FROM user WHERE id IN ($1, $2) AND gender IN (NULL)",
+			wantArgs: []any{int64(1), int64(2)},
+		},
+		{
+			name:    "Not In",
+			bindVar: BindQuestion,
+			build: func(b *SQLBuilder) {
+				b.Write("SELECT id FROM user WHERE ")
+				NotIn(b, "id", []int64{1, 2})
+				b.Write(" AND ")
+				NotIn(b, "gender", []int{})
+			},
+			wantSQL:  "SELECT id FROM user WHERE id NOT IN (?, ?) AND 1=1",
+			wantArgs: []any{int64(1), int64(2)},
+		},
+		{
+			name:    "Column Prefixed By Or",
+			bindVar: BindQuestion,
+			build: func(b *SQLBuilder) {
+				b.Write("SELECT id FROM user ")
+				b.Begin(ClauseWhere)
+				b.Write("orders > 0")
+				b.End()
+			},
+			wantSQL: "SELECT id FROM user WHERE orders > 0",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := NewSQLBuilder(tt.bindVar)
+			tt.build(b)
+			if got := b.SQL(); got != tt.wantSQL {
+				t.Errorf("SQL() got = %v, want %v", got, tt.wantSQL)
+			}
+			if got := b.Args(); !reflect.DeepEqual(got, tt.wantArgs) {
+				t.Errorf("Args() got = %v, want %v", got, tt.wantArgs)
+			}
+		})
+	}
+}
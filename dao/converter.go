@@ -0,0 +1,24 @@
+package dao
+
+import (
+	"database/sql/driver"
+)
+
+//ValueFunc return the driver.Valuer of v converted by fn when it is bound as a query arg,
+//the error of fn is returned by the Exec or Query
+func ValueFunc[T any, D any](v T, fn func(T) (D, error)) driver.Valuer {
+	return &valueFunc[T, D]{v: v, fn: fn}
+}
+
+type valueFunc[T any, D any] struct {
+	v  T
+	fn func(T) (D, error)
+}
+
+func (v *valueFunc[T, D]) Value() (driver.Value, error) {
+	d, err := v.fn(v.v)
+	if err != nil {
+		return nil, err
+	}
+	return driver.DefaultParameterConverter.ConvertValue(d)
+}
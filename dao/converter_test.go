@@ -0,0 +1,46 @@
+package dao
+
+import (
+	"database/sql/driver"
+	"errors"
+	"strconv"
+	"testing"
+)
+
+type status uint8
+
+func TestValueFunc(t *testing.T) {
+	formatErr := errors.New("unknown status")
+	format := func(s status) (string, error) {
+		if s > 2 {
+			return "", formatErr
+		}
+		return "s" + strconv.Itoa(int(s)), nil
+	}
+	tests := []struct {
+		name    string
+		valuer  driver.Valuer
+		want    driver.Value
+		wantErr error
+	}{
+		{name: "String", valuer: ValueFunc(status(1), format), want: "s1"},
+		{name: "Error", valuer: ValueFunc(status(3), format), wantErr: formatErr},
+		{
+			name:   "Convert To Driver Value",
+			valuer: ValueFunc(status(2), func(s status) (uint8, error) { return uint8(s), nil }),
+			want:   int64(2),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.valuer.Value()
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("Value() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("Value() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
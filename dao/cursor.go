@@ -0,0 +1,78 @@
+package dao
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+//ErrInvalidCursor the cursor is not encoded by EncodeCursor or the keyset of the query is changed
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+//ErrInvalidLimit the limit of the cursor request is not positive, it is returned before querying
+var ErrInvalidLimit = errors.New("invalid limit")
+
+//CursorRequest request the rows after the cursor, it is cheaper than the offset paging on the large tables,
+//because the rows before the cursor are skipped by the index of the keyset
+type CursorRequest struct {
+	After string //the Next of the previous page, empty for the first page
+	Limit int    //the max rows of the page, it must be positive, otherwise ErrInvalidLimit is returned
+}
+
+//CursorPage the rows after the cursor and the cursor of the next page
+type CursorPage[T any] struct {
+	Items []T
+	Next  string //the cursor to request the next page, empty if there is no next page
+}
+
+//HasNext return true if there is a next page
+func (p *CursorPage[T]) HasNext() bool {
+	return len(p.Next) > 0
+}
+
+//NewCursorPage return the page of the first limit items, the items are queried with limit+1 rows,
+//the next cursor is encoded from the keyset of the last item of the page if there are more items
+func NewCursorPage[T any](items []T, limit int, keyset func(T) []any) (*CursorPage[T], error) {
+	page := &CursorPage[T]{Items: items}
+	if limit <= 0 || len(items) <= limit {
+		return page, nil
+	}
+	page.Items = items[:limit]
+	next, err := EncodeCursor(keyset(page.Items[limit-1])...)
+	if err != nil {
+		return nil, err
+	}
+	page.Next = next
+	return page, nil
+}
+
+//EncodeCursor encode the keyset values of a row as an url safe cursor
+func EncodeCursor(values ...any) (string, error) {
+	data, err := json.Marshal(values)
+	if err != nil {
+		return "", fmt.Errorf("encode cursor fail: %w", err)
+	}
+	return base64.RawURLEncoding.EncodeToString(data), nil
+}
+
+//DecodeCursor decode the cursor into the pointers of the keyset values in the order they are encoded
+func DecodeCursor(cursor string, values ...any) error {
+	data, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return fmt.Errorf("%w: %s", ErrInvalidCursor, err)
+	}
+	var raws []json.RawMessage
+	if err = json.Unmarshal(data, &raws); err != nil {
+		return fmt.Errorf("%w: %s", ErrInvalidCursor, err)
+	}
+	if len(raws) != len(values) {
+		return fmt.Errorf("%w: want %d values got %d", ErrInvalidCursor, len(values), len(raws))
+	}
+	for i, raw := range raws {
+		if err = json.Unmarshal(raw, values[i]); err != nil {
+			return fmt.Errorf("%w: %s", ErrInvalidCursor, err)
+		}
+	}
+	return nil
+}
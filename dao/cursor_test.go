@@ -0,0 +1,79 @@
+package dao
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestDecodeCursor(t *testing.T) {
+	createdAt, id := time.Date(2022, 10, 1, 8, 30, 0, 0, time.UTC), int64(42)
+	cursor, err := EncodeCursor(createdAt, id)
+	if err != nil {
+		t.Fatalf("EncodeCursor() error = %v", err)
+	}
+	tests := []struct {
+		name    string
+		cursor  string
+		values  []any
+		want    []any
+		wantErr error
+	}{
+		{
+			name:   "Keyset",
+			cursor: cursor,
+			values: []any{new(time.Time), new(int64)},
+			want:   []any{&createdAt, &id},
+		},
+		{name: "Not Base64", cursor: "!", values: []any{new(int64)}, wantErr: ErrInvalidCursor},
+		{name: "Wrong Number Of Values", cursor: cursor, values: []any{new(int64)}, wantErr: ErrInvalidCursor},
+		{
+			name:    "Wrong Type",
+			cursor:  cursor,
+			values:  []any{new(int64), new(int64)},
+			wantErr: ErrInvalidCursor,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := DecodeCursor(tt.cursor, tt.values...)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("DecodeCursor() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if tt.wantErr == nil && !reflect.DeepEqual(tt.values, tt.want) {
+				t.Errorf("DecodeCursor() got = %v, want %v", tt.values, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewCursorPage(t *testing.T) {
+	keyset := func(item int) []any {
+		return []any{item}
+	}
+	next, _ := EncodeCursor(2)
+	tests := []struct {
+		name  string
+		items []int
+		limit int
+		want  *CursorPage[int]
+	}{
+		{name: "Has Next", items: []int{1, 2, 3}, limit: 2, want: &CursorPage[int]{Items: []int{1, 2}, Next: next}},
+		{name: "Last Page", items: []int{1, 2}, limit: 2, want: &CursorPage[int]{Items: []int{1, 2}}},
+		{name: "Empty", items: nil, limit: 2, want: &CursorPage[int]{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := NewCursorPage(tt.items, tt.limit, keyset)
+			if err != nil {
+				t.Errorf("NewCursorPage() error = %v", err)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("NewCursorPage() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
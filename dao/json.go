@@ -0,0 +1,42 @@
+package dao
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"reflect"
+)
+
+//UnmarshalJSON unmarshal the scanned json column into v, v is untouched if the column is NULL or empty
+func UnmarshalJSON(data []byte, v any) error {
+	if len(data) == 0 {
+		return nil
+	}
+	return json.Unmarshal(data, v)
+}
+
+//JSONValue return the driver.Valuer of v marshaled as a json text when it is bound as a query arg,
+//it is a text instead of bytes because mysql can not create a json value from a binary string.
+//A nil v, e.g. a nil pointer, map or slice, is bound as NULL instead of the json text null
+func JSONValue(v any) driver.Valuer {
+	return ValueFunc(v, marshalJSON)
+}
+
+func marshalJSON(v any) (any, error) {
+	if isNil(v) {
+		return nil, nil
+	}
+	data, err := json.Marshal(v)
+	return string(data), err
+}
+
+func isNil(v any) bool {
+	if v == nil {
+		return true
+	}
+	value := reflect.ValueOf(v)
+	switch value.Kind() {
+	case reflect.Pointer, reflect.Map, reflect.Slice, reflect.Interface:
+		return value.IsNil()
+	}
+	return false
+}
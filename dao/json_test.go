@@ -0,0 +1,66 @@
+package dao
+
+import (
+	"reflect"
+	"testing"
+)
+
+type profile struct {
+	Nickname string   `json:"nickname"`
+	Hobbies  []string `json:"hobbies,omitempty"`
+}
+
+func TestUnmarshalJSON(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    []byte
+		want    *profile
+		wantErr bool
+	}{
+		{name: "Object", data: []byte(`{"nickname":"lucy","hobbies":["go"]}`),
+			want: &profile{Nickname: "lucy", Hobbies: []string{"go"}}},
+		{name: "NULL", data: nil, want: &profile{Nickname: "origin"}},
+		{name: "Invalid", data: []byte(`{`), want: &profile{Nickname: "origin"}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := &profile{Nickname: "origin"}
+			if err := UnmarshalJSON(tt.data, got); (err != nil) != tt.wantErr {
+				t.Errorf("UnmarshalJSON() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("UnmarshalJSON() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestJSONValue(t *testing.T) {
+	var nilProfile *profile
+	var nilMap map[string]string
+	var nilSlice []string
+	tests := []struct {
+		name string
+		v    any
+		want any
+	}{
+		{name: "Struct", v: profile{Nickname: "lucy"}, want: `{"nickname":"lucy"}`},
+		{name: "Empty Slice", v: []string{}, want: `[]`},
+		{name: "Nil", v: nil, want: nil},
+		{name: "Nil Pointer", v: nilProfile, want: nil},
+		{name: "Nil Map", v: nilMap, want: nil},
+		{name: "Nil Slice", v: nilSlice, want: nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := JSONValue(tt.v).Value()
+			if err != nil {
+				t.Fatalf("Value() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("Value() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,32 @@
+package dao
+
+import (
+	"database/sql"
+)
+
+//ScanMap scan the current row into a map keyed by the column names,
+//the text columns returned as []byte by the driver are converted to strings
+func ScanMap(rows *sql.Rows) (map[string]any, error) {
+	columns, err := rows.Columns()
+	if err != nil {
+		return nil, err
+	}
+	values := make([]any, len(columns))
+	dest := make([]any, len(columns))
+	for i := range values {
+		dest[i] = &values[i]
+	}
+	err = rows.Scan(dest...)
+	if err != nil {
+		return nil, err
+	}
+	row := make(map[string]any, len(columns))
+	for i, column := range columns {
+		if bytes, ok := values[i].([]byte); ok {
+			row[column] = string(bytes)
+			continue
+		}
+		row[column] = values[i]
+	}
+	return row, nil
+}
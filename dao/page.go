@@ -0,0 +1,26 @@
+package dao
+
+//Page the rows of a page and the total rows of all the pages
+type Page[T any] struct {
+	Items    []T
+	Page     int
+	PageSize int
+	Total    int64
+}
+
+//TotalPages return the number of the pages
+func (p *Page[T]) TotalPages() int64 {
+	if p.PageSize <= 0 {
+		return 0
+	}
+	return (p.Total + int64(p.PageSize) - 1) / int64(p.PageSize)
+}
+
+//Slice the rows of a page and whether there is a next page,
+//it is cheaper than Page because the total rows are not counted
+type Slice[T any] struct {
+	Items    []T
+	Page     int
+	PageSize int
+	HasNext  bool
+}
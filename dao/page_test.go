@@ -0,0 +1,25 @@
+package dao
+
+import "testing"
+
+func TestPage_TotalPages(t *testing.T) {
+	tests := []struct {
+		name     string
+		pageSize int
+		total    int64
+		want     int64
+	}{
+		{name: "Empty", pageSize: 10, total: 0, want: 0},
+		{name: "Full", pageSize: 10, total: 20, want: 2},
+		{name: "Partial", pageSize: 10, total: 21, want: 3},
+		{name: "Zero Page Size", pageSize: 0, total: 21, want: 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := &Page[int]{PageSize: tt.pageSize, Total: tt.total}
+			if got := p.TotalPages(); got != tt.want {
+				t.Errorf("TotalPages() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
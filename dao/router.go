@@ -0,0 +1,48 @@
+package dao
+
+import (
+	"context"
+	"database/sql"
+	"sync/atomic"
+
+	"github.com/gomelon/melon/data"
+)
+
+//Router route the queries of generated dao to the primary or the replicas
+type Router interface {
+	//Primary return the executor of the primary,
+	//it is the transaction when ctx is in a transaction
+	Primary(ctx context.Context) data.SQLExecutor
+	//Replica return the executor of a replica,
+	//it is the transaction of the primary when ctx is in a transaction
+	Replica(ctx context.Context) data.SQLExecutor
+}
+
+//SQLRouter route writes, master reads and queries in transaction to the primary of the SQLTXManager,
+//other reads are routed to the replicas in round-robin, and to the primary if there is no replica
+type SQLRouter struct {
+	tm       *data.SQLTXManager
+	replicas []*sql.DB
+	next     uint64
+}
+
+//NewSQLRouter SQLRouter provider
+//+autowire.Provider
+func NewSQLRouter(tm *data.SQLTXManager, replicas ...*sql.DB) *SQLRouter {
+	return &SQLRouter{
+		tm:       tm,
+		replicas: replicas,
+	}
+}
+
+func (r *SQLRouter) Primary(ctx context.Context) data.SQLExecutor {
+	return r.tm.OriginTXOrDB(ctx)
+}
+
+func (r *SQLRouter) Replica(ctx context.Context) data.SQLExecutor {
+	if len(r.replicas) == 0 || r.tm.TX(ctx) != nil {
+		return r.tm.OriginTXOrDB(ctx)
+	}
+	next := atomic.AddUint64(&r.next, 1)
+	return r.replicas[next%uint64(len(r.replicas))]
+}
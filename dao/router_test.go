@@ -0,0 +1,62 @@
+package dao
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"testing"
+
+	"github.com/gomelon/melon/data"
+)
+
+type fakeConnector struct {
+}
+
+func (c *fakeConnector) Connect(_ context.Context) (driver.Conn, error) {
+	return nil, errors.New("fake connector can not connect")
+}
+
+func (c *fakeConnector) Driver() driver.Driver {
+	return nil
+}
+
+func TestSQLRouter(t *testing.T) {
+	primary := sql.OpenDB(&fakeConnector{})
+	replica1 := sql.OpenDB(&fakeConnector{})
+	replica2 := sql.OpenDB(&fakeConnector{})
+	tm := data.NewSqlTxManager("test", primary)
+	ctx := context.Background()
+
+	tests := []struct {
+		name        string
+		router      *SQLRouter
+		wantPrimary data.SQLExecutor
+		wantReplica []data.SQLExecutor
+	}{
+		{
+			name:        "Without Replica",
+			router:      NewSQLRouter(tm),
+			wantPrimary: primary,
+			wantReplica: []data.SQLExecutor{primary, primary},
+		},
+		{
+			name:        "Round Robin Replicas",
+			router:      NewSQLRouter(tm, replica1, replica2),
+			wantPrimary: primary,
+			wantReplica: []data.SQLExecutor{replica2, replica1, replica2},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.router.Primary(ctx); got != tt.wantPrimary {
+				t.Errorf("Primary() got = %v, want %v", got, tt.wantPrimary)
+			}
+			for i, want := range tt.wantReplica {
+				if got := tt.router.Replica(ctx); got != want {
+					t.Errorf("Replica() #%d got = %v, want %v", i, got, want)
+				}
+			}
+		})
+	}
+}
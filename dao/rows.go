@@ -0,0 +1,70 @@
+package dao
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+)
+
+//Break return it from the callback of a streaming select method to stop iterating the rows without error
+var Break = errors.New("break iterating rows")
+
+//ErrReturnedRows the rows returned by a batch insert are more or fewer than the inserted rows,
+//the generated ids can not be filled in the order of the rows
+var ErrReturnedRows = errors.New("returned rows mismatch")
+
+//Rows the iterator of the rows scanned into T, the rows are closed when the iteration is done or fails,
+//Close must be called if the iteration is stopped early
+type Rows[T any] struct {
+	ctx  context.Context
+	rows *sql.Rows
+	scan func(rows *sql.Rows) (T, error)
+	item T
+	err  error
+}
+
+//NewRows the iteration fails with the error of ctx when ctx is canceled
+func NewRows[T any](ctx context.Context, rows *sql.Rows, scan func(rows *sql.Rows) (T, error)) *Rows[T] {
+	return &Rows[T]{
+		ctx:  ctx,
+		rows: rows,
+		scan: scan,
+	}
+}
+
+//Next scan the next row into Item, return false if there is no more row or the iteration fails
+func (r *Rows[T]) Next() bool {
+	if r.err != nil {
+		return false
+	}
+	if r.err = r.ctx.Err(); r.err != nil {
+		_ = r.rows.Close()
+		return false
+	}
+	if !r.rows.Next() {
+		return false
+	}
+	r.item, r.err = r.scan(r.rows)
+	if r.err != nil {
+		_ = r.rows.Close()
+		return false
+	}
+	return true
+}
+
+//Item return the row scanned by Next
+func (r *Rows[T]) Item() T {
+	return r.item
+}
+
+//Err return the error of the iteration
+func (r *Rows[T]) Err() error {
+	if r.err != nil {
+		return r.err
+	}
+	return r.rows.Err()
+}
+
+func (r *Rows[T]) Close() error {
+	return r.rows.Close()
+}
@@ -0,0 +1,43 @@
+package dao
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/gomelon/melon/data/query"
+)
+
+//ErrUnsortable the sort field is not a selected column of the result, or the direction is unknown
+var ErrUnsortable = errors.New("unsortable")
+
+//OrderBy return the ORDER BY clause of the sorts, e.g. ORDER BY `created_at` DESC, `id` ASC,
+//the field of a sort is the field name or the column of the result, it is mapped to the column expression by columns,
+//it fails if the field is not in columns, so that the sorts from the user input can not inject sql
+func OrderBy(sorts []*query.Sort, columns map[string]string) (string, error) {
+	var builder strings.Builder
+	builder.Grow(64)
+	builder.WriteString("ORDER BY ")
+	for i, sort := range sorts {
+		column, ok := columns[sort.FieldName()]
+		if !ok {
+			return "", fmt.Errorf("%w: field %s", ErrUnsortable, sort.FieldName())
+		}
+		var direction string
+		switch {
+		case len(sort.Direction()) == 0 || strings.EqualFold(string(sort.Direction()), string(query.DirectionAsc)):
+			direction = "ASC"
+		case strings.EqualFold(string(sort.Direction()), string(query.DirectionDesc)):
+			direction = "DESC"
+		default:
+			return "", fmt.Errorf("%w: direction %s", ErrUnsortable, sort.Direction())
+		}
+		if i > 0 {
+			builder.WriteString(", ")
+		}
+		builder.WriteString(column)
+		builder.WriteString(" ")
+		builder.WriteString(direction)
+	}
+	return builder.String(), nil
+}
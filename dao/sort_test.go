@@ -0,0 +1,46 @@
+package dao
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/gomelon/melon/data/query"
+)
+
+func TestOrderBy(t *testing.T) {
+	columns := map[string]string{"Id": "`id`", "id": "`id`", "CreatedAt": "`created_at`", "created_at": "`created_at`"}
+	tests := []struct {
+		name    string
+		sorts   []*query.Sort
+		want    string
+		wantErr error
+	}{
+		{
+			name:  "Field And Column",
+			sorts: []*query.Sort{query.NewSort("CreatedAt", query.DirectionDesc), query.NewSort("id", "")},
+			want:  "ORDER BY `created_at` DESC, `id` ASC",
+		},
+		{
+			name:    "Injection",
+			sorts:   []*query.Sort{query.NewSort("id; DROP TABLE user", query.DirectionAsc)},
+			wantErr: ErrUnsortable,
+		},
+		{
+			name:    "Unknown Direction",
+			sorts:   []*query.Sort{query.NewSort("id", "Random")},
+			wantErr: ErrUnsortable,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := OrderBy(tt.sorts, columns)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("OrderBy() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("OrderBy() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
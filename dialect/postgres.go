@@ -0,0 +1,55 @@
+package dialect
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/gomelon/melon/data/engine"
+	"github.com/huandu/xstrings"
+)
+
+func UsePostgres() {
+	postgres := NewPostgres()
+	engine.Engines[postgres.Dialect()] = postgres
+}
+
+//Postgres the engine of postgres
+type Postgres struct {
+}
+
+func NewPostgres() *Postgres {
+	return &Postgres{}
+}
+
+func (p *Postgres) Dialect() string {
+	return "postgres"
+}
+
+func (p *Postgres) Escape(str string) string {
+	if strings.HasPrefix(str, `"`) {
+		return str
+	}
+	return fmt.Sprintf(`"%s"`, str)
+}
+
+func (p *Postgres) BuildColumn(str string) string {
+	return xstrings.ToSnakeCase(str)
+}
+
+//BuildContains the arg is cast to text, postgres can not infer the type of a bindvar of concat,
+//:::: is unescaped to :: by compiling the named query, it must be separated from the named arg
+func (p *Postgres) BuildContains(str string) string {
+	return fmt.Sprintf("LIKE CONCAT('%%', %s ::::text, '%%')", str)
+}
+
+func (p *Postgres) BuildStartsWith(str string) string {
+	return fmt.Sprintf("LIKE CONCAT(%s ::::text, '%%')", str)
+}
+
+func (p *Postgres) BuildEndsWith(str string) string {
+	return fmt.Sprintf("LIKE CONCAT('%%', %s ::::text)", str)
+}
+
+func (p *Postgres) BuildLimit(offset, limit string) string {
+	return fmt.Sprintf("LIMIT %s OFFSET %s", limit, offset)
+}
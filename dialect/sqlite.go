@@ -0,0 +1,53 @@
+package dialect
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/gomelon/melon/data/engine"
+	"github.com/huandu/xstrings"
+)
+
+func UseSQLite() {
+	sqlite := NewSQLite()
+	engine.Engines[sqlite.Dialect()] = sqlite
+}
+
+//SQLite the engine of sqlite, the dialect is sqlite3 as the bind type of sqlx
+type SQLite struct {
+}
+
+func NewSQLite() *SQLite {
+	return &SQLite{}
+}
+
+func (s *SQLite) Dialect() string {
+	return "sqlite3"
+}
+
+func (s *SQLite) Escape(str string) string {
+	if strings.HasPrefix(str, `"`) || strings.HasPrefix(str, "`") {
+		return str
+	}
+	return fmt.Sprintf(`"%s"`, str)
+}
+
+func (s *SQLite) BuildColumn(str string) string {
+	return xstrings.ToSnakeCase(str)
+}
+
+func (s *SQLite) BuildContains(str string) string {
+	return fmt.Sprintf("LIKE '%%' || %s || '%%'", str)
+}
+
+func (s *SQLite) BuildStartsWith(str string) string {
+	return fmt.Sprintf("LIKE %s || '%%'", str)
+}
+
+func (s *SQLite) BuildEndsWith(str string) string {
+	return fmt.Sprintf("LIKE '%%' || %s", str)
+}
+
+func (s *SQLite) BuildLimit(offset, limit string) string {
+	return fmt.Sprintf("LIMIT %s OFFSET %s", limit, offset)
+}
@@ -0,0 +1,472 @@
+package sqlmap
+
+import (
+	"fmt"
+	"go/types"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/gomelon/melon/third_party/sqlx"
+	"github.com/gomelon/sqlmap/dao"
+	"github.com/gomelon/sqlmap/parser"
+)
+
+//the actions of the dynamic query fragments, e.g.
+//    SELECT * FROM user {{where}} {{if .name}} AND name = :name {{end}} {{if .gender}} AND gender = :gender {{end}} {{end}}
+//    UPDATE user {{set}} {{if .name}} name = :name, {{end}} {{if .birthday}} birthday = :birthday, {{end}} {{end}} WHERE id = :id
+//the if fragment is kept when its arg is not zero, it can be a param or a struct param field, e.g. .name, .user.Name,
+//the where and set fragments are omitted when they are empty,
+//otherwise the leading AND or OR of where and the trailing comma of set are trimmed
+const (
+	actionIf    = "if"
+	actionElse  = "else"
+	actionEnd   = "end"
+	actionWhere = "where"
+	actionSet   = "set"
+)
+
+//fragment a text or an action of the dynamic query
+type fragment struct {
+	action   string //empty if it is a text
+	text     string //the text, or the arg of the if action, e.g. .name
+	children []*fragment
+	elses    []*fragment //the children of the else branch of the if action
+}
+
+//DynamicSQL the go code to build the sql and the args of a dynamic query at runtime,
+//the sql is built by _builder, a *dao.SQLBuilder
+type DynamicSQL struct {
+	Code []string
+}
+
+//inClausePattern matches the text before the named arg of an IN clause, e.g. id IN (:ids)
+var inClausePattern = regexp.MustCompile(`(?i)\bIN\s*\(\s*$`)
+
+//notInClausePattern matches the text before the named arg of a NOT IN clause, e.g. id NOT IN (:ids)
+var notInClausePattern = regexp.MustCompile(`(?i)\s*\bNOT\s+IN\s*\(\s*$`)
+
+//closeClausePattern matches the text after the named arg of an IN clause
+var closeClausePattern = regexp.MustCompile(`^\s*\)`)
+
+//isDynamicQuery return true if the query has the fragment actions
+func isDynamicQuery(query string) bool {
+	return strings.Contains(query, "{{")
+}
+
+//parseFragments parse the dynamic query to the fragments
+func parseFragments(query string) ([]*fragment, error) {
+	root := &fragment{}
+	stack := []*fragment{root}
+	inElse := false
+	appendFragment := func(frag *fragment) {
+		parent := stack[len(stack)-1]
+		if inElse {
+			parent.elses = append(parent.elses, frag)
+		} else {
+			parent.children = append(parent.children, frag)
+		}
+	}
+	for len(query) > 0 {
+		start := strings.Index(query, "{{")
+		if start < 0 {
+			appendFragment(&fragment{text: query})
+			break
+		}
+		if start > 0 {
+			appendFragment(&fragment{text: query[:start]})
+		}
+		end := strings.Index(query[start:], "}}")
+		if end < 0 {
+			return nil, fmt.Errorf("unclosed action %s", query[start:])
+		}
+		action := strings.Fields(query[start+2 : start+end])
+		query = query[start+end+2:]
+		if len(action) == 0 {
+			return nil, fmt.Errorf("empty action")
+		}
+		switch action[0] {
+		case actionIf:
+			if len(action) != 2 || !strings.HasPrefix(action[1], ".") {
+				return nil, fmt.Errorf("if action must have an arg, e.g. {{if .name}}")
+			}
+			frag := &fragment{action: actionIf, text: action[1]}
+			appendFragment(frag)
+			stack, inElse = append(stack, frag), false
+		case actionWhere, actionSet:
+			frag := &fragment{action: action[0]}
+			appendFragment(frag)
+			stack, inElse = append(stack, frag), false
+		case actionElse:
+			if parent := stack[len(stack)-1]; parent.action != actionIf || inElse {
+				return nil, fmt.Errorf("unexpected {{else}}")
+			}
+			inElse = true
+		case actionEnd:
+			if len(stack) == 1 {
+				return nil, fmt.Errorf("unexpected {{end}}")
+			}
+			stack, inElse = stack[:len(stack)-1], false
+		default:
+			return nil, fmt.Errorf("unsupported action {{%s}}", strings.Join(action, " "))
+		}
+		if len(action) > 1 && action[0] != actionIf {
+			return nil, fmt.Errorf("unexpected arg of action {{%s}}", strings.Join(action, " "))
+		}
+	}
+	if len(stack) > 1 {
+		return nil, fmt.Errorf("missing {{end}} of {{%s}}", stack[len(stack)-1].action)
+	}
+	return root.children, nil
+}
+
+//renderQuery render the dynamic query with all the if fragments kept,
+//the rendered query is a named query as a static query to parse the columns and the args
+func renderQuery(query string) (string, error) {
+	fragments, err := parseFragments(query)
+	if err != nil {
+		return "", err
+	}
+	builder := dao.NewSQLBuilder(dao.BindQuestion)
+	renderFragments(builder, fragments)
+	return builder.SQL(), nil
+}
+
+func renderFragments(builder *dao.SQLBuilder, fragments []*fragment) {
+	for _, frag := range fragments {
+		switch frag.action {
+		case "":
+			builder.Write(frag.text)
+		case actionIf:
+			renderFragments(builder, frag.children)
+		case actionWhere, actionSet:
+			builder.Begin(strings.ToUpper(frag.action))
+			renderFragments(builder, frag.children)
+			builder.End()
+		}
+	}
+}
+
+//DynamicSQL return the code to build the query of querier at runtime,
+//nil if the query is static and has no slice arg expanded in an IN clause
+func (f *functions) DynamicSQL(method types.Object, mapper *Mapper, querier Querier) (
+	dynamicSQL *DynamicSQL, err error) {
+
+	query := querier.GetQuery()
+	if !f.runtimeSQL(method, query) {
+		return
+	}
+
+	var bindVar string
+	switch sqlx.BindType(f.Dialect(mapper)) {
+	case sqlx.QUESTION:
+		bindVar = "BindQuestion"
+	case sqlx.DOLLAR:
+		bindVar = "BindDollar"
+	default:
+		err = fmt.Errorf("unsupported dialect of dynamic query,dialect=%s,method=%s", f.Dialect(mapper),
+			method.String())
+		return
+	}
+
+	if sel, ok := querier.(*Select); ok {
+		query, err = f.expandDynamicStars(method, mapper, sel)
+		if err != nil {
+			return
+		}
+	}
+	fragments, err := parseFragments(query)
+	if err != nil {
+		err = fmt.Errorf("parse dynamic query fail: %w, method=[%s],sql=%s", err, method.String(), query)
+		return
+	}
+
+	daoPkg := f.importTracker.Import("github.com/gomelon/sqlmap/dao")
+	dynamicSQL = &DynamicSQL{
+		Code: []string{fmt.Sprintf("_builder := %s.NewSQLBuilder(%s.%s)", daoPkg, daoPkg, bindVar)},
+	}
+	params := f.methodParamsWithoutCtx(method)
+	usedParams := make(map[types.Object]bool, len(params))
+	dynamicSQL.Code, err = f.fragmentsCode(dynamicSQL.Code, fragments, params, usedParams, daoPkg)
+	if err == nil {
+		err = f.checkUnusedParams(params, usedParams)
+	}
+	if err != nil {
+		err = fmt.Errorf("parse dynamic query fail: %w, method=[%s],sql=%s", err, method.String(), query)
+		dynamicSQL = nil
+	}
+	return
+}
+
+//runtimeSQL return true if the query is built at runtime,
+//the query is dynamic or it has a slice arg expanded in an IN clause
+func (f *functions) runtimeSQL(method types.Object, query string) bool {
+	if isDynamicQuery(query) {
+		return true
+	}
+	texts, names := splitNamedArgs(query)
+	params := f.methodParamsWithoutCtx(method)
+	for i, name := range names {
+		if _, ok := f.inArg(name, params); ok && inClausePattern.MatchString(texts[i]) {
+			return true
+		}
+	}
+	return false
+}
+
+//checkDynamicQuery the paging, the cursor and the sort param rewrite the sql at generate time,
+//so they are not supported by the select built at runtime
+func (f *functions) checkDynamicQuery(method types.Object, sel *Select) error {
+	if !f.runtimeSQL(method, sel.Query) {
+		return nil
+	}
+	if f.PagerParam(method) != nil || f.CursorParam(method) != nil || f.SortParam(method) != nil ||
+		len(sel.Cursor) > 0 {
+		return fmt.Errorf("dynamic query or IN slice arg can not be used with the pager, "+
+			"the cursor or the sort param,method=%s", method.String())
+	}
+	return nil
+}
+
+//inArg return the expr of the slice param or the slice struct param field which the query name bind to,
+//it is expanded to a bindvar per item in an IN clause,
+//the slice is bound as one arg if it is []byte, it has a value converter or it implements driver.Valuer
+func (f *functions) inArg(queryName string, params []types.Object) (expr string, ok bool) {
+	for _, param := range params {
+		if param.Name() == queryName {
+			return queryName, f.expandable(param.Type())
+		}
+	}
+	param, columnField, err := f.structField(queryName, params)
+	if err != nil || columnField.hasOption("json") {
+		return
+	}
+	return param.Name() + "." + columnField.fieldPath(), f.expandable(columnField.field.Type())
+}
+
+func (f *functions) expandable(typ types.Type) bool {
+	slice, ok := typ.Underlying().(*types.Slice)
+	if !ok {
+		return false
+	}
+	if types.Identical(slice.Elem(), types.Typ[types.Byte]) {
+		return false
+	}
+	return f.converter(typ).value == nil && !f.hasMethod(typ, "Value")
+}
+
+//expandDynamicStars expand the stars of the dynamic select as RewriteSelectStmt
+func (f *functions) expandDynamicStars(method types.Object, mapper *Mapper, sel *Select) (query string, err error) {
+	query = sel.Query
+	if f.RowMap(f.ItemType(method)) {
+		return
+	}
+	rendered, _, err := f.compileNamedQuery(sel.Query, f.Dialect(mapper))
+	if err != nil {
+		return
+	}
+	sqlParser, err := parser.New(f.Dialect(mapper), rendered)
+	if err != nil {
+		return
+	}
+	selectColumns, err := sqlParser.SelectColumns()
+	if err != nil {
+		return
+	}
+	return f.expandStars(method, query, selectColumns)
+}
+
+//fragmentsCode append the code to build the fragments to code, the params bound by the fragments are marked used
+func (f *functions) fragmentsCode(code []string, fragments []*fragment, params []types.Object,
+	usedParams map[types.Object]bool, daoPkg string) ([]string, error) {
+
+	var err error
+	for _, frag := range fragments {
+		switch frag.action {
+		case "":
+			texts, names := splitNamedArgs(frag.text)
+			for i, text := range texts {
+				var expr, operand string
+				var in, notIn bool
+				if i < len(names) {
+					expr, in = f.inArg(names[i], params)
+					in = in && inClausePattern.MatchString(text)
+					notIn = in && notInClausePattern.MatchString(text)
+				}
+				if notIn {
+					//the NOT IN predicate is built by dao.NotIn, it matches every row if the slice is empty
+					text, operand, texts[i+1], err = splitNotInClause(text, texts[i+1])
+					if err != nil {
+						return nil, fmt.Errorf("%w,name=%s", err, names[i])
+					}
+				}
+				if len(text) > 0 {
+					code = append(code, fmt.Sprintf("_builder.Write(%s)", strconv.Quote(text)))
+				}
+				if i == len(names) {
+					continue
+				}
+				arg, param, argErr := f.namedArg(names[i], params)
+				if argErr != nil {
+					return nil, argErr
+				}
+				usedParams[param] = true
+				switch {
+				case notIn:
+					code = append(code, fmt.Sprintf("%s.NotIn(_builder, %s, %s)", daoPkg, strconv.Quote(operand), expr))
+				case in:
+					code = append(code, fmt.Sprintf("%s.In(_builder, %s)", daoPkg, expr))
+				default:
+					code = append(code, fmt.Sprintf("_builder.Arg(%s)", arg))
+				}
+			}
+		case actionIf:
+			cond, param, condErr := f.conditionExpr(frag.text, params)
+			if condErr != nil {
+				return nil, condErr
+			}
+			usedParams[param] = true
+			code = append(code, fmt.Sprintf("if %s {", cond))
+			if code, err = f.fragmentsCode(code, frag.children, params, usedParams, daoPkg); err != nil {
+				return nil, err
+			}
+			if len(frag.elses) > 0 {
+				code = append(code, "} else {")
+				if code, err = f.fragmentsCode(code, frag.elses, params, usedParams, daoPkg); err != nil {
+					return nil, err
+				}
+			}
+			code = append(code, "}")
+		case actionWhere, actionSet:
+			clause := "ClauseWhere"
+			if frag.action == actionSet {
+				clause = "ClauseSet"
+			}
+			code = append(code, fmt.Sprintf("_builder.Begin(%s.%s)", daoPkg, clause))
+			if code, err = f.fragmentsCode(code, frag.children, params, usedParams, daoPkg); err != nil {
+				return nil, err
+			}
+			code = append(code, "_builder.End()")
+		}
+	}
+	return code, nil
+}
+
+//conditionExpr return the expr which is true if the arg of the if action is not zero,
+//the arg is a param or a struct param field, e.g. .name, .user.Name or .Name of the struct param
+func (f *functions) conditionExpr(arg string, params []types.Object) (
+	cond string, param types.Object, err error) {
+
+	name := strings.TrimPrefix(arg, ".")
+	for _, param = range params {
+		if param.Name() == name {
+			cond, err = f.nonZeroExpr(name, param.Type())
+			return
+		}
+	}
+	param, columnField, err := f.structField(name, params)
+	if err != nil {
+		return
+	}
+	cond, err = f.nonZeroExpr(param.Name()+"."+columnField.fieldPath(), columnField.field.Type())
+	return
+}
+
+//nonZeroExpr return the expr which is true if expr is not the zero value of typ
+func (f *functions) nonZeroExpr(expr string, typ types.Type) (string, error) {
+	switch underlying := typ.Underlying().(type) {
+	case *types.Pointer, *types.Interface, *types.Signature, *types.Chan:
+		return expr + " != nil", nil
+	case *types.Slice, *types.Map:
+		return "len(" + expr + ") > 0", nil
+	case *types.Basic:
+		switch {
+		case underlying.Info()&types.IsString != 0:
+			return "len(" + expr + ") > 0", nil
+		case underlying.Info()&types.IsBoolean != 0:
+			return expr, nil
+		case underlying.Info()&types.IsNumeric != 0:
+			return expr + " != 0", nil
+		}
+	}
+	if f.hasMethod(typ, "IsZero") {
+		return "!" + expr + ".IsZero()", nil
+	}
+	return "", fmt.Errorf("can not check zero value of %s for if action, "+
+		"the type must be a basic, pointer, slice or map type, or has IsZero method", expr)
+}
+
+//splitNamedArgs split the text by the named args as sqlx.CompileNamedQuery,
+//the texts are one more than the names, :: is unescaped to :
+func splitNamedArgs(text string) (texts []string, names []string) {
+	var builder strings.Builder
+	for i := 0; i < len(text); i++ {
+		c := text[i]
+		if c != ':' || i == len(text)-1 {
+			builder.WriteByte(c)
+			continue
+		}
+		next := text[i+1]
+		if next == ':' || next == '=' {
+			builder.WriteByte(':')
+			if next == '=' {
+				builder.WriteByte('=')
+			}
+			i++
+			continue
+		}
+		end := i + 1
+		for end < len(text) && isNameByte(text[end]) {
+			end++
+		}
+		if end == i+1 {
+			builder.WriteByte(c)
+			continue
+		}
+		texts = append(texts, builder.String())
+		builder.Reset()
+		names = append(names, text[i+1:end])
+		i = end - 1
+	}
+	texts = append(texts, builder.String())
+	return
+}
+
+//splitNotInClause split the operand of the NOT IN clause from the text before the named arg,
+//and trim the closing parenthesis from the text after it, e.g. "id NOT IN (" and ") order by id" to "", "id" and " order by id",
+//the operand is a column or a parenthesized expression, e.g. u.id, lower(name)
+func splitNotInClause(before, after string) (text, operand, rest string, err error) {
+	loc := notInClausePattern.FindStringIndex(before)
+	closing := closeClausePattern.FindStringIndex(after)
+	if loc == nil || closing == nil {
+		err = fmt.Errorf("NOT IN clause must be closed after the named arg")
+		return
+	}
+	end := loc[0]
+	start := end
+	if start > 0 && before[start-1] == ')' {
+		for depth := 0; start > 0; {
+			start--
+			if before[start] == ')' {
+				depth++
+			} else if before[start] == '(' {
+				depth--
+			}
+			if depth == 0 {
+				break
+			}
+		}
+	}
+	for start > 0 && (isNameByte(before[start-1]) || before[start-1] == '`' || before[start-1] == '"') {
+		start--
+	}
+	if start == end {
+		err = fmt.Errorf("can not find the operand of NOT IN clause")
+		return
+	}
+	return before[:start], before[start:end], after[closing[1]:], nil
+}
+
+func isNameByte(c byte) bool {
+	return c == '_' || c == '.' || c >= '0' && c <= '9' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z'
+}
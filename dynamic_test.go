@@ -0,0 +1,139 @@
+package sqlmap
+
+import (
+	"reflect"
+	"testing"
+)
+
+func Test_renderQuery(t *testing.T) {
+	tests := []struct {
+		name    string
+		query   string
+		want    string
+		wantErr bool
+	}{
+		{
+			name:  "Where",
+			query: "select id from user {{where}} {{if .name}} and name = :name {{end}} {{end}} order by id",
+			want:  "select id from user WHERE name = :name  order by id",
+		},
+		{
+			name:  "Set",
+			query: "update user {{set}} {{if .name}} name = :name, {{else}} name = '', {{end}} {{end}} where id = :id",
+			want:  "update user SET name = :name  where id = :id",
+		},
+		{
+			name:    "Missing End",
+			query:   "select id from user {{where}} {{if .name}} and name = :name {{end}}",
+			wantErr: true,
+		},
+		{
+			name:    "Unsupported Action",
+			query:   "select id from user {{range .ids}} {{end}}",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := renderQuery(tt.query)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("renderQuery() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("renderQuery() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_splitNamedArgs(t *testing.T) {
+	tests := []struct {
+		name      string
+		text      string
+		wantTexts []string
+		wantNames []string
+	}{
+		{name: "None", text: "order by id", wantTexts: []string{"order by id"}},
+		{
+			name:      "Names",
+			text:      " and name = :user.Name and gender = :gender",
+			wantTexts: []string{" and name = ", " and gender = ", ""},
+			wantNames: []string{"user.Name", "gender"},
+		},
+		{
+			name:      "Escaped",
+			text:      "created_at::::date = :day",
+			wantTexts: []string{"created_at::date = ", ""},
+			wantNames: []string{"day"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotTexts, gotNames := splitNamedArgs(tt.text)
+			if !reflect.DeepEqual(gotTexts, tt.wantTexts) || !reflect.DeepEqual(gotNames, tt.wantNames) {
+				t.Errorf("splitNamedArgs() got = %q %q, want %q %q", gotTexts, gotNames, tt.wantTexts, tt.wantNames)
+			}
+		})
+	}
+}
+
+func Test_splitNotInClause(t *testing.T) {
+	tests := []struct {
+		name        string
+		before      string
+		after       string
+		wantText    string
+		wantOperand string
+		wantRest    string
+		wantErr     bool
+	}{
+		{
+			name:        "Column",
+			before:      "select id from user where u.id not in (",
+			after:       ") order by id",
+			wantText:    "select id from user where ",
+			wantOperand: "u.id",
+			wantRest:    " order by id",
+		},
+		{
+			name:        "Expression",
+			before:      "select id from user where (lower(name)) NOT IN ( ",
+			after:       " )",
+			wantText:    "select id from user where ",
+			wantOperand: "(lower(name))",
+		},
+		{
+			name:        "Quoted",
+			before:      "select id from user where \"gender\" not in(",
+			after:       ")",
+			wantText:    "select id from user where ",
+			wantOperand: "\"gender\"",
+		},
+		{
+			name:    "Not Closed",
+			before:  "select id from user where id not in (",
+			after:   ", 1)",
+			wantErr: true,
+		},
+		{
+			name:    "No Operand",
+			before:  "not in (",
+			after:   ")",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotText, gotOperand, gotRest, err := splitNotInClause(tt.before, tt.after)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("splitNotInClause() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if gotText != tt.wantText || gotOperand != tt.wantOperand || gotRest != tt.wantRest {
+				t.Errorf("splitNotInClause() got = %q %q %q, want %q %q %q", gotText, gotOperand, gotRest,
+					tt.wantText, tt.wantOperand, tt.wantRest)
+			}
+		})
+	}
+}
@@ -9,27 +9,217 @@ import (
 	"github.com/gomelon/melon/data/query"
 	"github.com/gomelon/melon/third_party/sqlx"
 	"github.com/gomelon/meta"
+	"github.com/gomelon/sqlmap/dialect"
 	"github.com/gomelon/sqlmap/parser"
 	"github.com/huandu/xstrings"
 	"go/types"
+	"reflect"
+	"regexp"
+	"strconv"
 	"strings"
 	"text/template"
 )
 
+const (
+	ExecResultNone         = "None"
+	ExecResultRowsAffected = "RowsAffected"
+	ExecResultLastInsertId = "LastInsertId"
+	ExecResultEntity       = "Entity"
+)
+
+var (
+	insertKeywords = []string{"Insert", "Save", "Create"}
+	upsertKeywords = []string{"Upsert", "SaveOrUpdate"}
+)
+
+const (
+	PageResultPage  = "Page"
+	PageResultSlice = "Slice"
+)
+
+const updateKeyword = "Update"
+
+var (
+	byKeywordRegexp  = regexp.MustCompile("By[A-Z]")
+	andKeywordRegexp = regexp.MustCompile("And[A-Z]")
+)
+
+var defaultMaxPlaceholders = map[string]int{
+	"":         999,
+	"mysql":    65535,
+	"postgres": 65535,
+	"sqlite3":  999,
+}
+
+//BatchInsert the parts to build a multiple rows insert statement at runtime
+type BatchInsert struct {
+	Param      types.Object //the slice param to insert
+	Prefix     string       //e.g. INSERT INTO `user` (`name`, `gender`) VALUES
+	Row        string       //go expression of a row's placeholders, e.g. "(?, ?)"
+	Args       string       //args of the row _item, e.g. _item.Name, _item.Gender
+	NumColumns int
+	BatchSize  int        //max rows of a statement
+	IdField    *types.Var //the field to fill the generated id, nil if unsupported
+	IdFromLast bool       //the generated id is the id of the last row instead of the first row
+	Returning  string     //the RETURNING clause of the id column, the ids are scanned from the returned rows in order
+}
+
+//Scan the code to scan a row into the query result item
+type Scan struct {
+	Vars    []string      //the declarations of the temporaries, e.g. var _nullName sql.NullString
+	Args    string        //the args of rows.Scan, e.g. &_item.Id, &_nullName
+	Assigns []*ScanAssign //the assignments from the temporaries after scan
+}
+
+//ScanAssign the assignment from a temporary after scan, e.g. _item.Name = _nullName.String
+type ScanAssign struct {
+	Target string //e.g. _item.Name, empty if the value is a call which only returns an error
+	Value  string //e.g. _nullName.String
+	Err    bool   //the value is a call which returns an error as the last result
+}
+
+//Paging the parts to query a page of rows
+type Paging struct {
+	Param    types.Object //the pager param, e.g. pager query.Pager
+	Result   string       //PageResultPage or PageResultSlice
+	SQL      string       //the sql with the LIMIT clause
+	Args     string       //the args of the LIMIT clause, e.g. pager.PageSize(), pager.Offset(),
+	CountSQL string       //the sql to count the rows of all the pages, empty if the result is PageResultSlice
+}
+
+//CursorPaging the parts to query the rows after a cursor
+type CursorPaging struct {
+	Param    types.Object //the cursor param, e.g. cursor dao.CursorRequest
+	SQL      string       //the sql of the first page
+	AfterSQL string       //the sql of the pages after the cursor
+	Vars     []string     //the declarations of the keyset values decoded from the cursor, e.g. var _afterId int64
+	Decode   string       //the args of dao.DecodeCursor, e.g. &_afterId
+	Args     string       //the args of the keyset values, e.g. _afterId,
+	Keyset   string       //the keyset values of the _item to encode the next cursor, e.g. _item.Id
+}
+
+//Sorting the parts to replace the ORDER BY clause of the select by the sort param at runtime
+type Sorting struct {
+	Param types.Object //the sort param, e.g. sorts []*query.Sort
+	Head  string       //the sql before the ORDER BY clause
+	Tail  string       //the sql after the ORDER BY clause, e.g. LIMIT ?, empty if there is nothing after it
+	//Columns the allow-list of the sort fields, the field names and the columns of the selected columns
+	//to the expressions of the columns, e.g. CreatedAt -> u.created_at
+	Columns map[string]string
+}
+
+//scanTarget the item or a field of the item to scan a column into
+type scanTarget struct {
+	expr     string //e.g. _item.Name
+	name     string //the name of the temporary without prefix, e.g. Name
+	typ      types.Type
+	nullable bool //scan through a sql.Null* temporary
+	json     bool //scan through a []byte temporary and unmarshal it
+}
+
+//columnField a struct field and the column it maps to
+type columnField struct {
+	field   *types.Var
+	path    string //the selector of a nested field, e.g. Address.Phone, empty if it is a field of the struct
+	column  string
+	tagged  bool     //the column is named by the db tag
+	options []string //options of the db tag, e.g. pk, json, nullable, omitempty
+}
+
+//fieldPath return the selector of the field relative to the struct
+func (c *columnField) fieldPath() string {
+	if len(c.path) > 0 {
+		return c.path
+	}
+	return c.field.Name()
+}
+
+func (c *columnField) hasOption(option string) bool {
+	return containsString(c.options, option)
+}
+
+func containsObject(objs []types.Object, obj types.Object) bool {
+	for _, o := range objs {
+		if o == obj {
+			return true
+		}
+	}
+	return false
+}
+
+func containsString(strs []string, str string) bool {
+	for _, s := range strs {
+		if s == str {
+			return true
+		}
+	}
+	return false
+}
+
+//converter the converter funcs of a field type, see Converter
+type converter struct {
+	scan  types.Object //converts the scanned column to the field
+	value types.Object //converts the field to the query arg
+}
+
+//dialectAliases the other names of a dialect used by Mapper.Dialect
+var dialectAliases = map[string]string{
+	"sqlite":     "sqlite3",
+	"pgx":        "postgres",
+	"postgresql": "postgres",
+}
+
 type functions struct {
 	ruleParser    *data.RuleParser
 	pkgParser     *meta.PkgParser
 	metaParser    *meta.Parser
+	importTracker meta.ImportTracker
 	defaultEngine engine.Engine
+	engines       map[string]engine.Engine
+	pkgPath       string
+	convertFuncs  []string              //the qualified func names of the converters registered by options
+	converters    map[string]*converter //key is the qualified field type, nil until loaded
+	schemaPaths   []string              //the DDL files or directories registered by options
+	schema        *parser.Schema        //nil until loaded or if there is no schema path
+}
+
+type Option func(f *functions)
+
+//WithEngines register the engines to resolve Mapper.Dialect, the engine of the same dialect is replaced
+func WithEngines(engines ...engine.Engine) Option {
+	return func(f *functions) {
+		for _, dialectEngine := range engines {
+			f.engines[dialectEngine.Dialect()] = dialectEngine
+		}
+	}
+}
+
+//WithConverters register the converters by the qualified func names,
+//e.g. github.com/shopspring/decimal.NewFromString, see Converter for the signature of a converter.
+//The converters with MetaConverter in the generating package take precedence
+func WithConverters(funcNames ...string) Option {
+	return func(f *functions) {
+		f.convertFuncs = append(f.convertFuncs, funcNames...)
+	}
 }
 
-func NewFunctions(gen *meta.TmplPkgGen, defaultEngine engine.Engine) *functions {
-	return &functions{
+//NewFunctions the engine of a Mapper.Dialect is resolved by the engines of options,
+//then the built-in engines of mysql, postgres and sqlite, then the engines registered in engine.Engines
+func NewFunctions(gen *meta.TmplPkgGen, defaultEngine engine.Engine, opts ...Option) *functions {
+	f := &functions{
 		ruleParser:    data.NewRuleParser(),
 		pkgParser:     gen.PkgParser(),
 		metaParser:    gen.MetaParser(),
+		importTracker: gen.ImportTracker(),
 		defaultEngine: defaultEngine,
+		engines:       map[string]engine.Engine{},
+		pkgPath:       gen.PkgPath(),
 	}
+	WithEngines(engine.NewMySQL(), dialect.NewPostgres(), dialect.NewSQLite(), defaultEngine)(f)
+	for _, opt := range opts {
+		opt(f)
+	}
+	return f
 }
 
 func (f *functions) FuncMap() template.FuncMap {
@@ -37,12 +227,39 @@ func (f *functions) FuncMap() template.FuncMap {
 		"buildMapper":       f.BuildMapper,
 		"queryType":         f.QueryType,
 		"buildSelect":       f.BuildSelect,
+		"buildInsert":       f.BuildInsert,
+		"buildUpdate":       f.BuildUpdate,
+		"buildUpsert":       f.BuildUpsert,
 		"buildDelete":       f.BuildDelete,
 		"rewriteSelectStmt": f.RewriteSelectStmt,
+		"rewriteInsertStmt": f.RewriteInsertStmt,
+		"rewriteUpdateStmt": f.RewriteUpdateStmt,
+		"rewriteUpsertStmt": f.RewriteUpsertStmt,
 		"rewriteDeleteStmt": f.RewriteDeleteStmt,
-		"scanFields":        f.ScanFields,
+		"buildBatchInsert":  f.BuildBatchInsert,
+		"batchParam":        f.BatchParam,
+		"execResult":        f.ExecResult,
+		"entityParam":       f.EntityParam,
+		"idField":           f.IdField,
+		"buildScan":         f.BuildScan,
+		"returning":         f.Returning,
+		"rowMap":            f.RowMap,
+		"rowsItem":          f.RowsItem,
+		"callbackParam":     f.CallbackParam,
+		"itemType":          f.ItemType,
+		"pageResult":        f.PageResult,
+		"pagerParam":        f.PagerParam,
+		"buildPaging":       f.BuildPaging,
+		"cursorResult":      f.CursorResult,
+		"cursorParam":       f.CursorParam,
+		"buildCursorPaging": f.BuildCursorPaging,
+		"sortParam":         f.SortParam,
+		"buildSorting":      f.BuildSorting,
+		"dynamicSQL":        f.DynamicSQL,
+		"mapKey":            f.MapKey,
 		"queryArgs":         f.QueryArgs,
 		"dialect":           f.Dialect,
+		"multipleLines":     f.MultipleLines,
 	}
 }
 
@@ -50,7 +267,16 @@ func (f *functions) BuildMapper(obj types.Object) (*Mapper, error) {
 	objectMeta := f.metaParser.ObjectMeta(obj, MetaMapper)
 	mapper := &Mapper{}
 	err := objectMeta.MapTo(mapper)
-	return mapper, err
+	if err != nil {
+		return mapper, err
+	}
+	if f.engine(mapper) == nil {
+		return mapper, fmt.Errorf("unsupported dialect,dialect=%s,mapper=%s", mapper.Dialect, obj.String())
+	}
+	if err = f.loadConverters(); err != nil {
+		return mapper, err
+	}
+	return mapper, f.loadSchema()
 }
 
 func (f *functions) QueryType(method types.Object) (queryType string, err error) {
@@ -63,6 +289,21 @@ func (f *functions) QueryType(method types.Object) (queryType string, err error)
 		return
 	}
 
+	if f.hasKeyword(method.Name(), upsertKeywords) {
+		queryType = MetaUpsert
+		return
+	}
+
+	if f.hasKeyword(method.Name(), insertKeywords) {
+		queryType = MetaInsert
+		return
+	}
+
+	if strings.HasPrefix(method.Name(), updateKeyword) {
+		queryType = MetaUpdate
+		return
+	}
+
 	subject, err := f.ruleParser.ParseSubject(method.Name())
 	if err != nil {
 		return "", err
@@ -90,6 +331,15 @@ func (f *functions) BuildSelect(method types.Object, mapper *Mapper) (selectMeta
 	selectMeta = &Select{}
 	if selectMetaGroup != nil && len(selectMetaGroup) > 0 {
 		err = selectMetaGroup[0].MapTo(selectMeta)
+		if err != nil {
+			return
+		}
+	}
+	if err = f.checkSortParam(method, selectMeta); err != nil {
+		return
+	}
+	if len(selectMeta.Query) > 0 {
+		err = f.checkDynamicQuery(method, selectMeta)
 		return
 	}
 
@@ -120,12 +370,14 @@ func (f *functions) BuildSelect(method types.Object, mapper *Mapper) (selectMeta
 		}
 	}
 
-	sql, err := f.translateQuery(mapper, parsedQuery)
+	rowStruct, _ := f.underlyingType(f.ItemType(method)).Underlying().(*types.Struct)
+	sql, err := f.translateQuery(mapper, parsedQuery, rowStruct)
 	if err != nil {
 		return
 	}
 
 	selectMeta.Query = sql
+	err = f.checkDynamicQuery(method, selectMeta)
 	return
 }
 
@@ -134,6 +386,10 @@ func (f *functions) RewriteSelectStmt(method types.Object, mapper *Mapper, sel *
 
 	originQuery := sel.Query
 	query, _, err = f.compileNamedQuery(originQuery, dialect)
+	if err != nil {
+		err = fmt.Errorf("compile named query fail: %w,method=[%s],sql=%s", err, method.String(), originQuery)
+		return
+	}
 
 	sqlParser, err := parser.New(dialect, query)
 	if err != nil {
@@ -146,280 +402,2191 @@ func (f *functions) RewriteSelectStmt(method types.Object, mapper *Mapper, sel *
 		return
 	}
 
-	if len(selectColumns) == 1 && selectColumns[0].Alias == "*" {
-		queryResultObject := f.pkgParser.FirstResult(method)
-		rowType := f.pkgParser.UnderlyingType(queryResultObject.Type())
-		rowStruct, ok := rowType.Underlying().(*types.Struct)
-		if !ok {
-			err = fmt.Errorf("parse sql fail: query result must a struct when select *, method=[%s],sql=%s",
-				method.String(), originQuery)
+	//the columns of the row maps are decided at runtime
+	if !f.RowMap(f.ItemType(method)) {
+		if query, err = f.expandStars(method, query, selectColumns); err != nil {
 			return
 		}
+	}
+	err = f.checkSchema(method, mapper, query)
+	return
+}
+
+//expandStars expand the stars to the columns of the result struct, e.g. select u.*, a.phone -> select u.id, u.name, a.phone
+func (f *functions) expandStars(method types.Object, query string, selectColumns []*parser.Column) (
+	expanded string, err error) {
+
+	expanded = query
+	var rowStruct *types.Struct
+	for _, column := range selectColumns {
+		if column.Alias != "*" {
+			continue
+		}
+		if rowStruct == nil {
+			rowType := f.underlyingType(f.ItemType(method))
+			var ok bool
+			rowStruct, ok = rowType.Underlying().(*types.Struct)
+			if !ok {
+				err = fmt.Errorf("parse sql fail: query result must a struct when select *, method=[%s],sql=%s",
+					method.String(), query)
+				return
+			}
+		}
 
-		column := selectColumns[0]
-		numFields := rowStruct.NumFields()
-		columnNames := make([]string, 0, numFields)
-		for i := 0; i < numFields; i++ {
-			columnName := xstrings.ToSnakeCase(rowStruct.Field(i).Name())
-			columnNames = append(columnNames, f.connectTableQualifier(column.TableQualifier, columnName))
+		columnFields := f.columnFields(rowStruct)
+		columnNames := make([]string, 0, len(columnFields))
+		for _, columnField := range columnFields {
+			columnNames = append(columnNames, f.connectTableQualifier(column.TableQualifier, columnField.column))
 		}
 
 		qualifierStarStr := f.connectTableQualifier(column.TableQualifier, "*")
 		selectColumnStr := strings.Join(columnNames, ", ")
-		query = strings.Replace(query, qualifierStarStr, selectColumnStr, 1)
+		expanded = strings.Replace(expanded, qualifierStarStr, selectColumnStr, 1)
 	}
 	return
 }
 
-func (f *functions) BuildDelete(method types.Object, mapper *Mapper) (deleteMeta *Delete, err error) {
-	metaName, deleteMetaGroup, err := f.subjectMeta(method)
+func (f *functions) BuildInsert(method types.Object, mapper *Mapper) (insertMeta *Insert, err error) {
+	metaName, insertMetaGroup, err := f.subjectMeta(method)
 	if err != nil {
 		return
 	}
 
-	if len(metaName) > 0 && metaName != MetaDelete {
-		err = fmt.Errorf("expected %s but %s,method=%s", MetaDelete, metaName, method.String())
+	if len(metaName) > 0 && metaName != MetaInsert {
+		err = fmt.Errorf("expected %s but %s,method=%s", MetaInsert, metaName, method.String())
 		return
 	}
 
-	deleteMeta = &Delete{}
-	if deleteMetaGroup != nil && len(deleteMetaGroup) > 0 {
-		err = deleteMetaGroup[0].MapTo(deleteMeta)
+	insertMeta = &Insert{}
+	if insertMetaGroup != nil && len(insertMetaGroup) > 0 {
+		err = insertMetaGroup[0].MapTo(insertMeta)
+		if err != nil || len(insertMeta.Query) > 0 {
+			return
+		}
+	} else if !f.hasKeyword(method.Name(), insertKeywords) {
+		err = fmt.Errorf("can not parse method to insert query, method name must starts with %v,method=%s",
+			insertKeywords, method.String())
 		return
 	}
 
-	parsedQuery, err := f.ruleParser.Parse(method.Name())
-	if parsedQuery == nil || parsedQuery.Subject() != query.SubjectDelete {
-		if deleteMeta != nil {
-			err = fmt.Errorf("can not parse method to query,method=%s, possible reasons is %w",
-				method.String(), err)
-		} else {
-			err = nil
-		}
+	entityParam := f.structParam(method)
+	//the rows of a batch insert share the columns, so omitempty is not applied
+	omitempty := entityParam != nil
+	if entityParam == nil {
+		entityParam = f.BatchParam(method)
+	}
+	if entityParam == nil {
+		err = fmt.Errorf("can not parse method to insert query, a struct param is required,method=%s",
+			method.String())
 		return
 	}
 
-	parsedQuery = parsedQuery.With(query.WithTable(query.NewTable(mapper.Table)))
-	if parsedQuery.FilterGroup() != nil {
-		toArgMethodParams := f.methodParamsWithoutCtx(method)
-		namedArgs := make([]string, 0, len(toArgMethodParams))
-		for _, param := range toArgMethodParams {
-			namedArgs = append(namedArgs, param.Name())
-		}
-		err = parsedQuery.FilterGroup().FillNamedArgs(namedArgs)
-		if err != nil {
-			return
+	entityStruct := f.underlyingType(entityParam.Type()).(*types.Struct)
+	dialectEngine := f.engine(mapper)
+	insertColumnFields := f.insertColumnFields(entityStruct)
+	columns := make([]string, 0, len(insertColumnFields))
+	namedArgs := make([]string, 0, len(insertColumnFields))
+	//the zero omitempty fields are left out at runtime, the query is built by DynamicSQL
+	var omitemptyColumns, omitemptyArgs string
+	for _, columnField := range insertColumnFields {
+		column, namedArg := dialectEngine.Escape(columnField.column), ":"+columnField.column
+		if omitempty && columnField.hasOption("omitempty") {
+			cond := fmt.Sprintf("{{if .%s.%s}}", entityParam.Name(), columnField.fieldPath())
+			omitemptyColumns += cond + ", " + column + "{{end}}"
+			omitemptyArgs += cond + ", " + namedArg + "{{end}}"
+			continue
 		}
+		columns = append(columns, column)
+		namedArgs = append(namedArgs, namedArg)
 	}
-
-	sql, err := f.translateQuery(mapper, parsedQuery)
-	if err != nil {
+	if len(columns) == 0 && len(omitemptyColumns) > 0 {
+		err = fmt.Errorf("can not parse method to insert query, a column without omitempty is required,method=%s",
+			method.String())
 		return
 	}
 
-	deleteMeta.Query = sql
+	insertMeta.Query = fmt.Sprintf("INSERT INTO %s (%s%s) VALUES (%s%s)", dialectEngine.Escape(mapper.Table),
+		strings.Join(columns, ", "), omitemptyColumns, strings.Join(namedArgs, ", "), omitemptyArgs)
+	//postgres has no LastInsertId
+	if dialectEngine.Dialect() == "postgres" && f.structParam(method) != nil {
+		var returning string
+		returning, err = f.returningId(method, dialectEngine, entityStruct)
+		insertMeta.Query += returning
+	}
 	return
 }
 
-func (f *functions) RewriteDeleteStmt(_ types.Object, mapper *Mapper, deleteMeta *Delete) (query string, err error) {
-	dialect := f.Dialect(mapper)
-	query, _, err = f.compileNamedQuery(deleteMeta.Query, dialect)
+//returningId return the RETURNING clause of the id column if the method returns the generated id or the entity
+func (f *functions) returningId(method types.Object, dialectEngine engine.Engine, entityStruct *types.Struct) (
+	returning string, err error) {
+
+	execResult, err := f.ExecResult(method)
+	if err != nil || (execResult != ExecResultEntity && execResult != ExecResultLastInsertId) {
+		return
+	}
+	idField := f.idFieldOf(entityStruct)
+	if idField == nil {
+		return
+	}
+	for _, columnField := range f.columnFields(entityStruct) {
+		if columnField.field == idField {
+			returning = " RETURNING " + dialectEngine.Escape(columnField.column)
+		}
+	}
 	return
 }
 
-func (f *functions) ScanFields(method types.Object, mapper *Mapper, sql string, item string) (string, error) {
+func (f *functions) RewriteInsertStmt(method types.Object, mapper *Mapper, insertMeta *Insert) (query string, err error) {
 	dialect := f.Dialect(mapper)
-
-	var err error
-
-	sqlParser, err := parser.New(dialect, sql)
+	query, _, err = f.compileNamedQuery(insertMeta.Query, dialect)
 	if err != nil {
-		return "", fmt.Errorf("parse sql fail: %w, method=[%s],sql=%s", err, method.String(), sql)
-	}
-	columns, err := sqlParser.SelectColumns()
-	if err != nil {
-		return "", fmt.Errorf("parse sql fail: %w,method=[%s],sql=%s", err, method.String(), sql)
+		return
 	}
+	err = f.checkSchema(method, mapper, query)
+	return
+}
 
-	queryResultObject := f.pkgParser.FirstResult(method)
-	rowType := f.pkgParser.UnderlyingType(queryResultObject.Type())
+//BuildBatchInsert build the parts of a multiple rows insert statement for the slice param of method,
+//the statement is split into batches so that the placeholders of a batch are not more than MaxPlaceholders
+func (f *functions) BuildBatchInsert(method types.Object, mapper *Mapper, insertMeta *Insert) (
+	batchInsert *BatchInsert, err error) {
 
-	var result string
-	switch rowType := rowType.(type) {
-	case *types.Struct:
-		result, err = f.scanFieldsForStruct(rowType, columns, item)
-	case *types.Basic:
-		result, err = f.scanFieldsForBasic(rowType, columns, item)
+	batchParam := f.BatchParam(method)
+	if batchParam == nil {
+		err = fmt.Errorf("can not find slice param for batch insert,method=%s", method.String())
+		return
 	}
-
-	if err != nil {
-		return "", fmt.Errorf("parse sql fail:%w, method=[%s],sql=%s", err, method.String(), sql)
+	if insertMeta.Query != "" && f.hasMetaQuery(method) {
+		err = fmt.Errorf("batch insert does not support specified query,method=%s", method.String())
+		return
 	}
 
-	return result, nil
-}
-
-func (f *functions) QueryArgs(method types.Object, mapper *Mapper, querier Querier) (nameArgsStr string, err error) {
 	dialect := f.Dialect(mapper)
-	originQuery := querier.GetQuery()
-	_, queryNames, err := f.compileNamedQuery(originQuery, dialect)
-	if err != nil {
+	dialectEngine := f.engine(mapper)
+	entityStruct := f.underlyingType(batchParam.Type()).(*types.Struct)
+	insertColumnFields := f.insertColumnFields(entityStruct)
+	numColumns := len(insertColumnFields)
+	if numColumns == 0 {
+		err = fmt.Errorf("batch insert has no column to insert,method=%s", method.String())
 		return
 	}
 
-	toArgMethodParams := f.methodParamsWithoutCtx(method)
-	if len(queryNames) == 0 {
-		f.positionArgsStr(toArgMethodParams)
-		return
+	columns := make([]string, 0, numColumns)
+	args := make([]string, 0, numColumns)
+	for _, columnField := range insertColumnFields {
+		columns = append(columns, dialectEngine.Escape(columnField.column))
+		args = append(args, f.bindFieldArg("_item."+columnField.field.Name(), columnField))
 	}
 
-	nameArgsStr, err = f.nameArgsStr(queryNames, toArgMethodParams)
+	row, err := f.batchRowPlaceholders(dialect, numColumns)
 	if err != nil {
-		err = fmt.Errorf("parse sql fail: %w, method=[%s],sql=%s", err, method.String(), originQuery)
+		err = fmt.Errorf("%w,method=%s", err, method.String())
+		return
 	}
-	return
-}
-
-func (f *functions) Dialect(mapper *Mapper) string {
-	return f.engine(mapper).Dialect()
-}
 
-func (f *functions) positionArgsStr(toArgsMethodParams []types.Object) string {
-	if len(toArgsMethodParams) == 0 {
-		return ""
+	maxPlaceholders := insertMeta.MaxPlaceholders
+	if maxPlaceholders <= 0 {
+		maxPlaceholders = defaultMaxPlaceholders[dialect]
+	}
+	if maxPlaceholders <= 0 {
+		maxPlaceholders = defaultMaxPlaceholders[""]
+	}
+	batchSize := maxPlaceholders / numColumns
+	if batchSize <= 0 {
+		batchSize = 1
 	}
-	argsBuilder := strings.Builder{}
-	argsBuilder.Grow(64)
-	for _, queryName := range toArgsMethodParams {
-		argsBuilder.WriteString(queryName.Name())
-		argsBuilder.WriteRune(',')
 
+	batchInsert = &BatchInsert{
+		Param:      batchParam,
+		Prefix:     fmt.Sprintf("INSERT INTO %s (%s) VALUES ", dialectEngine.Escape(mapper.Table), strings.Join(columns, ", ")),
+		Row:        row,
+		Args:       strings.Join(args, ", "),
+		NumColumns: numColumns,
+		BatchSize:  batchSize,
 	}
-	return argsBuilder.String()
+	switch dialect {
+	case "postgres":
+		//the ids are filled by RETURNING only if they are declared in the order of the rows,
+		//postgres does not guarantee the order of the returned rows
+		if !insertMeta.ConsecutiveIds {
+			break
+		}
+		batchInsert.IdField = f.idFieldOf(entityStruct)
+		for _, columnField := range f.columnFields(entityStruct) {
+			if batchInsert.IdField != nil && columnField.field == batchInsert.IdField {
+				batchInsert.Returning = " RETURNING " + dialectEngine.Escape(columnField.column)
+			}
+		}
+		if len(batchInsert.Returning) == 0 {
+			batchInsert.IdField = nil
+		}
+	case "mysql", "sqlite3":
+		//the ids are filled by LastInsertId only if they are declared consecutive,
+		//it is the id of the first row in mysql and the id of the last row in sqlite
+		if insertMeta.ConsecutiveIds {
+			batchInsert.IdField = f.idFieldOf(entityStruct)
+			batchInsert.IdFromLast = dialect == "sqlite3"
+		}
+	}
+
+	//the row of NULLs makes the statement to validate, the placeholders of the rows are built at runtime
+	nulls := strings.TrimSuffix(strings.Repeat("NULL, ", numColumns), ", ")
+	err = f.checkSchema(method, mapper, batchInsert.Prefix+"("+nulls+")"+batchInsert.Returning)
+	return
 }
 
-func (f *functions) nameArgsStr(queryNames []string, toArgsMethodParams []types.Object) (string, error) {
-	if len(toArgsMethodParams) != len(queryNames) {
-		err := fmt.Errorf("wrong number of args, want %d got %d", len(queryNames), len(toArgsMethodParams))
-		return "", err
+func (f *functions) BuildUpdate(method types.Object, mapper *Mapper) (updateMeta *Update, err error) {
+	metaName, updateMetaGroup, err := f.subjectMeta(method)
+	if err != nil {
+		return
 	}
 
-	argsBuilder := strings.Builder{}
-	argsBuilder.Grow(64)
-	for _, queryName := range queryNames {
-		argsBuilder.WriteString(queryName)
-		argsBuilder.WriteRune(',')
-
+	if len(metaName) > 0 && metaName != MetaUpdate {
+		err = fmt.Errorf("expected %s but %s,method=%s", MetaUpdate, metaName, method.String())
+		return
 	}
-	return argsBuilder.String(), nil
-}
 
-func (f *functions) scanFieldsForBasic(_ *types.Basic, columns []*parser.Column,
-	item string) (string, error) {
-	if len(columns) > 1 || columns[0].Alias == "*" {
-		return "", errors.New("when the query result is a basic type, select must be a specified field")
+	updateMeta = &Update{}
+	if updateMetaGroup != nil && len(updateMetaGroup) > 0 {
+		err = updateMetaGroup[0].MapTo(updateMeta)
+		return
 	}
-	return "&" + item, nil
-}
 
-func (f *functions) scanFieldsForStruct(rowType *types.Struct, columns []*parser.Column,
-	item string) (string, error) {
-	if len(columns) == 1 && columns[0].Alias == "*" {
-		return f.scanFieldsForStar(rowType, item)
-	} else {
-		return f.scanFieldsForMultipleColumn(rowType, columns, item)
+	methodName := method.Name()
+	if !strings.HasPrefix(methodName, updateKeyword) {
+		err = fmt.Errorf("can not parse method to update query, method name must starts with %s,method=%s",
+			updateKeyword, method.String())
+		return
 	}
 
-}
+	//UpdateNameAndGenderById -> set fields [Name Gender], filter part ById
+	setFields, filterPart := f.splitUpdateMethodName(methodName[len(updateKeyword):])
 
-func (f *functions) scanFieldsForStar(rowType *types.Struct, item string) (string, error) {
-	numFields := rowType.NumFields()
-	toScanFieldNames := make([]string, 0, numFields)
-	for i := 0; i < numFields; i++ {
-		fieldName := rowType.Field(i).Name()
-		toScanFieldName := "&" + item + "." + fieldName
-		toScanFieldNames = append(toScanFieldNames, toScanFieldName)
+	//parse the filter part as a find query, e.g. ById -> FindById
+	parsedQuery, err := f.ruleParser.Parse(query.SubjectFind.Name() + filterPart)
+	if err != nil {
+		err = fmt.Errorf("can not parse method to query,method=%s, possible reasons is %w",
+			method.String(), err)
+		return
+	}
+	if parsedQuery.FilterGroup() == nil {
+		err = fmt.Errorf("update method must have a filter, e.g. UpdateById,method=%s", method.String())
+		return
 	}
-	return strings.Join(toScanFieldNames, ", "), nil
-}
 
-func (f *functions) scanFieldsForMultipleColumn(rowType *types.Struct, columns []*parser.Column,
-	item string) (result string, err error) {
+	entityParam := f.structParam(method)
+	if entityParam == nil && len(setFields) == 0 {
+		err = fmt.Errorf("can not parse method to update query, "+
+			"a struct param or the fields to set is required, e.g. UpdateNameById,method=%s", method.String())
+		return
+	}
 
-	toScanFieldNames := make([]string, 0, len(columns))
-	structFieldNames := make(map[string]bool, rowType.NumFields())
-	for i := 0; i < rowType.NumFields(); i++ {
-		structFieldNames[rowType.Field(i).Name()] = true
+	toArgMethodParams := make([]types.Object, 0, 4)
+	for _, param := range f.methodParamsWithoutCtx(method) {
+		if param == entityParam {
+			continue
+		}
+		toArgMethodParams = append(toArgMethodParams, param)
 	}
-	for _, column := range columns {
-		if column.Alias == "*" {
-			err = fmt.Errorf("msql: unsupported * mixed with specified fields query")
+
+	dialectEngine := f.engine(mapper)
+	var sets, omitemptySets []string
+	var entityStruct *types.Struct
+	if entityParam == nil {
+		//the leading params are the values to set, the remaining params are the filter args
+		if len(toArgMethodParams) < len(setFields) {
+			err = fmt.Errorf("wrong number of args, want at least %d got %d,method=%s",
+				len(setFields), len(toArgMethodParams), method.String())
+			return
+		}
+		var setColumnFields []*columnField
+		entityStruct, setColumnFields, err = f.setColumnFields(method, setFields)
+		if err != nil {
+			err = fmt.Errorf("can not parse method to update query, %w,method=%s", err, method.String())
 			return
 		}
-		fieldName := xstrings.ToCamelCase(column.Alias)
-		if !structFieldNames[fieldName] {
-			err = fmt.Errorf("msql: can't find field name in struct, field=%s,rowType=%s",
-				fieldName, rowType.String())
+		sets = make([]string, 0, len(setFields))
+		for i, columnField := range setColumnFields {
+			column := dialectEngine.Escape(columnField.column)
+			sets = append(sets, fmt.Sprintf("%s = :%s", column, toArgMethodParams[i].Name()))
+		}
+		toArgMethodParams = toArgMethodParams[len(setFields):]
+	} else {
+		sets, omitemptySets, err = f.entityUpdateSets(entityParam, setFields, dialectEngine)
+		if err != nil {
+			err = fmt.Errorf("can not parse method to update query, %w,method=%s", err, method.String())
+			return
 		}
-
-		toScanFieldName := "&" + item + "." + fieldName
-		toScanFieldNames = append(toScanFieldNames, toScanFieldName)
 	}
-	result = strings.Join(toScanFieldNames, ", ")
-	return
-}
 
-func (f *functions) connectTableQualifier(tableQualifier, column string) string {
-	if len(tableQualifier) == 0 {
-		return column
+	namedArgs := make([]string, 0, len(toArgMethodParams))
+	for _, param := range toArgMethodParams {
+		namedArgs = append(namedArgs, param.Name())
 	}
-	return tableQualifier + "." + column
-}
-
-func (f *functions) engine(mapper *Mapper) engine.Engine {
-	dialect := mapper.Dialect
-	if len(dialect) == 0 || dialect == f.defaultEngine.Dialect() {
-		return f.defaultEngine
+	err = parsedQuery.FilterGroup().FillNamedArgs(namedArgs)
+	if err != nil {
+		return
 	}
-	//TODO support multiple engine
-	return nil
-}
 
-func (f *functions) translateQuery(mapper *Mapper, q *query.Query) (sql string, err error) {
-	dialectEngine := f.engine(mapper)
-	if dialectEngine == nil {
-		err = fmt.Errorf("unsupported dialect,dialect=%s", mapper.Dialect)
+	if entityParam != nil {
+		entityStruct = f.underlyingType(entityParam.Type()).(*types.Struct)
+	}
+	whereStr, err := f.translateFilterGroup(mapper, parsedQuery.FilterGroup(), entityStruct)
+	if err != nil {
 		return
 	}
-	translator := query.NewRDBTranslator(dialectEngine)
-	return translator.Translate(context.Background(), q)
-}
 
-func (f *functions) compileNamedQuery(namedQuery, dialect string) (query string, names []string, err error) {
-	bindType := sqlx.BindType(dialect)
-	if bindType == 0 {
-		err = fmt.Errorf("unsupported dialect,dialect=%s", dialect)
-		return
+	setClause := "SET " + strings.Join(sets, ", ")
+	if len(omitemptySets) > 0 {
+		//the zero omitempty fields are left out at runtime, the trailing comma is trimmed by the set action
+		setClause = "{{set}} " + strings.Join(append(sets, ""), ", ") + strings.Join(omitemptySets, " ") + " {{end}}"
 	}
-	query, names, err = sqlx.CompileNamedQuery([]byte(namedQuery), bindType)
+	updateMeta.Query = fmt.Sprintf("UPDATE %s %s WHERE %s", dialectEngine.Escape(mapper.Table), setClause, whereStr)
 	return
 }
 
-func (f *functions) subjectMeta(method types.Object) (metaName string, group meta.Group, err error) {
-	metaGroups := f.metaParser.ObjectMetaGroups(method, MetaNames...)
-	if len(metaGroups) > 1 {
-		err = fmt.Errorf("method can not use multiple %v,method=%s", MetaNames, method.String())
-	} else if len(metaGroups) == 1 {
-		for k, v := range metaGroups {
-			metaName = k
-			group = v
-			break
-		}
+func (f *functions) RewriteUpdateStmt(method types.Object, mapper *Mapper, updateMeta *Update) (query string, err error) {
+	dialect := f.Dialect(mapper)
+	query, _, err = f.compileNamedQuery(updateMeta.Query, dialect)
+	if err != nil {
+		return
 	}
+	err = f.checkSchema(method, mapper, query)
 	return
 }
 
-func (f *functions) methodParamsWithoutCtx(method types.Object) []types.Object {
+func (f *functions) BuildUpsert(method types.Object, mapper *Mapper) (upsertMeta *Upsert, err error) {
+	metaName, upsertMetaGroup, err := f.subjectMeta(method)
+	if err != nil {
+		return
+	}
+
+	if len(metaName) > 0 && metaName != MetaUpsert {
+		err = fmt.Errorf("expected %s but %s,method=%s", MetaUpsert, metaName, method.String())
+		return
+	}
+
+	upsertMeta = &Upsert{}
+	if upsertMetaGroup != nil && len(upsertMetaGroup) > 0 {
+		err = upsertMetaGroup[0].MapTo(upsertMeta)
+		if err != nil || len(upsertMeta.Query) > 0 {
+			return
+		}
+	}
+
+	entityParam := f.structParam(method)
+	if entityParam == nil {
+		err = fmt.Errorf("can not parse method to upsert query, a struct param is required,method=%s",
+			method.String())
+		return
+	}
+
+	entityStruct := f.underlyingType(entityParam.Type()).(*types.Struct)
+	columnFields := f.columnFields(entityStruct)
+	findColumnField := func(name string) (*columnField, error) {
+		for _, columnField := range columnFields {
+			if columnField.column == name || columnField.field.Name() == name {
+				return columnField, nil
+			}
+		}
+		return nil, fmt.Errorf("can not find column %s in %s,method=%s",
+			name, entityParam.Type().String(), method.String())
+	}
+
+	dialect := f.Dialect(mapper)
+	insertColumnFields := f.insertColumnFields(entityStruct)
+	conflictKeys := f.splitColumns(upsertMeta.ConflictKeys)
+	conflictColumns := make(map[string]bool, len(conflictKeys))
+	var omittedIdColumnField *columnField
+	for _, conflictKey := range conflictKeys {
+		var conflictColumnField *columnField
+		conflictColumnField, err = findColumnField(conflictKey)
+		if err != nil {
+			return
+		}
+		conflictColumns[conflictColumnField.column] = true
+		if conflictColumnField.field != f.idFieldOf(entityStruct) {
+			continue
+		}
+		//the id must be inserted when it is the conflict key, but a zero id is omitted in postgres and sqlite,
+		//otherwise it is inserted as 0 and the row is updated by the next new entity
+		if dialect == "mysql" {
+			insertColumnFields = append([]*columnField{conflictColumnField}, insertColumnFields...)
+		} else {
+			omittedIdColumnField = conflictColumnField
+		}
+	}
+
+	var updateColumns []string
+	if len(upsertMeta.UpdateColumns) > 0 {
+		for _, updateColumn := range f.splitColumns(upsertMeta.UpdateColumns) {
+			var updateColumnField *columnField
+			updateColumnField, err = findColumnField(updateColumn)
+			if err != nil {
+				return
+			}
+			updateColumns = append(updateColumns, updateColumnField.column)
+		}
+	} else {
+		for _, columnField := range insertColumnFields {
+			if conflictColumns[columnField.column] {
+				continue
+			}
+			updateColumns = append(updateColumns, columnField.column)
+		}
+	}
+
+	dialectEngine := f.engine(mapper)
+	columns := make([]string, 0, len(insertColumnFields))
+	namedArgs := make([]string, 0, len(insertColumnFields))
+	for _, columnField := range insertColumnFields {
+		columns = append(columns, dialectEngine.Escape(columnField.column))
+		namedArgs = append(namedArgs, fmt.Sprintf(":%s.%s", entityParam.Name(), columnField.field.Name()))
+	}
+	columnsStr, namedArgsStr := strings.Join(columns, ", "), strings.Join(namedArgs, ", ")
+	if omittedIdColumnField != nil {
+		//the id is inserted only if it is not zero, the query is built at runtime, see DynamicSQL
+		idField := entityParam.Name() + "." + omittedIdColumnField.field.Name()
+		var separator string
+		if len(columns) > 0 {
+			separator = ", "
+		}
+		columnsStr = fmt.Sprintf("{{if .%s}}%s%s{{end}}%s", idField,
+			dialectEngine.Escape(omittedIdColumnField.column), separator, columnsStr)
+		namedArgsStr = fmt.Sprintf("{{if .%s}}:%s%s{{end}}%s", idField, idField, separator, namedArgsStr)
+	}
+
+	var onConflict string
+	switch dialect {
+	case "mysql":
+		sets := make([]string, 0, len(updateColumns)+1)
+		if idField := f.idFieldOf(entityStruct); idField != nil {
+			//make LastInsertId return the id of the updated row
+			idColumnField, _ := findColumnField(idField.Name())
+			idColumn := dialectEngine.Escape(idColumnField.column)
+			sets = append(sets, fmt.Sprintf("%s = LAST_INSERT_ID(%s)", idColumn, idColumn))
+		}
+		for _, updateColumn := range updateColumns {
+			column := dialectEngine.Escape(updateColumn)
+			sets = append(sets, fmt.Sprintf("%s = VALUES(%s)", column, column))
+		}
+		onConflict = "ON DUPLICATE KEY UPDATE " + strings.Join(sets, ", ")
+	case "postgres", "sqlite", "sqlite3":
+		if len(conflictKeys) == 0 {
+			err = fmt.Errorf("upsert of %s must specify ConflictKeys,method=%s", dialect, method.String())
+			return
+		}
+		escapedConflictKeys := make([]string, 0, len(conflictKeys))
+		for _, conflictKey := range conflictKeys {
+			escapedConflictKeys = append(escapedConflictKeys, dialectEngine.Escape(conflictKey))
+		}
+		sets := make([]string, 0, len(updateColumns))
+		for _, updateColumn := range updateColumns {
+			column := dialectEngine.Escape(updateColumn)
+			sets = append(sets, fmt.Sprintf("%s = EXCLUDED.%s", column, column))
+		}
+		onConflict = fmt.Sprintf("ON CONFLICT (%s) DO UPDATE SET %s",
+			strings.Join(escapedConflictKeys, ", "), strings.Join(sets, ", "))
+		//LastInsertId is unchanged by the update, so return the id of the updated row
+		var returning string
+		returning, err = f.returningId(method, dialectEngine, entityStruct)
+		if err != nil {
+			return
+		}
+		onConflict += returning
+	default:
+		err = fmt.Errorf("unsupported upsert dialect,dialect=%s,method=%s", dialect, method.String())
+		return
+	}
+
+	upsertMeta.Query = fmt.Sprintf("INSERT INTO %s (%s) VALUES (%s) %s", dialectEngine.Escape(mapper.Table),
+		columnsStr, namedArgsStr, onConflict)
+	return
+}
+
+func (f *functions) RewriteUpsertStmt(method types.Object, mapper *Mapper, upsertMeta *Upsert) (query string, err error) {
+	dialect := f.Dialect(mapper)
+	query, _, err = f.compileNamedQuery(upsertMeta.Query, dialect)
+	if err != nil {
+		return
+	}
+	err = f.checkSchema(method, mapper, query)
+	return
+}
+
+func (f *functions) BuildDelete(method types.Object, mapper *Mapper) (deleteMeta *Delete, err error) {
+	metaName, deleteMetaGroup, err := f.subjectMeta(method)
+	if err != nil {
+		return
+	}
+
+	if len(metaName) > 0 && metaName != MetaDelete {
+		err = fmt.Errorf("expected %s but %s,method=%s", MetaDelete, metaName, method.String())
+		return
+	}
+
+	deleteMeta = &Delete{}
+	if deleteMetaGroup != nil && len(deleteMetaGroup) > 0 {
+		err = deleteMetaGroup[0].MapTo(deleteMeta)
+		return
+	}
+
+	parsedQuery, err := f.ruleParser.Parse(method.Name())
+	if parsedQuery == nil || parsedQuery.Subject() != query.SubjectDelete {
+		if deleteMeta != nil {
+			err = fmt.Errorf("can not parse method to query,method=%s, possible reasons is %w",
+				method.String(), err)
+		} else {
+			err = nil
+		}
+		return
+	}
+
+	parsedQuery = parsedQuery.With(query.WithTable(query.NewTable(mapper.Table)))
+	if parsedQuery.FilterGroup() != nil {
+		toArgMethodParams := f.methodParamsWithoutCtx(method)
+		namedArgs := make([]string, 0, len(toArgMethodParams))
+		for _, param := range toArgMethodParams {
+			namedArgs = append(namedArgs, param.Name())
+		}
+		err = parsedQuery.FilterGroup().FillNamedArgs(namedArgs)
+		if err != nil {
+			return
+		}
+	}
+
+	sql, err := f.translateQuery(mapper, parsedQuery, nil)
+	if err != nil {
+		return
+	}
+
+	deleteMeta.Query = sql
+	return
+}
+
+func (f *functions) RewriteDeleteStmt(method types.Object, mapper *Mapper, deleteMeta *Delete) (query string, err error) {
+	dialect := f.Dialect(mapper)
+	query, _, err = f.compileNamedQuery(deleteMeta.Query, dialect)
+	if err != nil {
+		return
+	}
+	err = f.checkSchema(method, mapper, query)
+	return
+}
+
+func (f *functions) BuildScan(method types.Object, mapper *Mapper, sql string, item string, sel *Select) (
+	scan *Scan, err error) {
+
+	dialect := f.Dialect(mapper)
+
+	sqlParser, err := parser.New(dialect, sql)
+	if err != nil {
+		return nil, fmt.Errorf("parse sql fail: %w, method=[%s],sql=%s", err, method.String(), sql)
+	}
+	columns, err := sqlParser.SelectColumns()
+	if err != nil {
+		return nil, fmt.Errorf("parse sql fail: %w,method=[%s],sql=%s", err, method.String(), sql)
+	}
+
+	itemType := f.ItemType(method)
+
+	var targets []*scanTarget
+	if rowType, ok := f.underlyingType(itemType).(*types.Struct); ok && f.nestedStruct(itemType) != nil {
+		targets, err = f.scanTargetsForStruct(rowType, columns, item)
+	} else {
+		targets, err = f.scanTargetsForSingleColumn(itemType, columns, item)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("parse sql fail:%w, method=[%s],sql=%s", err, method.String(), sql)
+	}
+	if err = f.checkScanTypes(method, sqlParser, targets, sql); err != nil {
+		return nil, err
+	}
+
+	scan, err = f.scanOf(targets, sel.Nullable)
+	if err != nil {
+		return nil, fmt.Errorf("parse sql fail:%w, method=[%s],sql=%s", err, method.String(), sql)
+	}
+	return
+}
+
+//scanOf return the code to scan the targets, the nullable targets are scanned through the sql.Null* temporaries,
+//the targets of a type with a converter are scanned through the temporaries of the converter param type,
+//the json targets are scanned through the []byte temporaries then unmarshalled
+func (f *functions) scanOf(targets []*scanTarget, nullable bool) (*Scan, error) {
+	scan := &Scan{}
+	args := make([]string, 0, len(targets))
+	for _, target := range targets {
+		if target.json {
+			temp := "_json" + target.name
+			scan.Vars = append(scan.Vars, fmt.Sprintf("var %s []byte", temp))
+			args = append(args, "&"+temp)
+			scan.Assigns = append(scan.Assigns, &ScanAssign{
+				Value: fmt.Sprintf("%s.UnmarshalJSON(%s, &%s)",
+					f.importTracker.Import("github.com/gomelon/sqlmap/dao"), temp, target.expr),
+				Err: true,
+			})
+			continue
+		}
+
+		scanType := target.typ
+		scanConverter := f.converter(target.typ).scan
+		if scanConverter != nil {
+			scanType = f.convertSignature(scanConverter).Params().At(0).Type()
+		}
+		scanNull := (nullable || target.nullable) && !f.acceptNull(scanType)
+		if scanConverter == nil && !scanNull {
+			args = append(args, "&"+target.expr)
+			continue
+		}
+
+		var value string
+		if scanNull {
+			nullType, valueField, valueType := f.nullTypeOf(scanType)
+			if len(nullType) == 0 {
+				return nil, fmt.Errorf("unsupported nullable type %s of %s", scanType.String(), target.expr)
+			}
+			temp := "_null" + target.name
+			scan.Vars = append(scan.Vars, fmt.Sprintf("var %s %s.%s", temp, f.importTracker.Import("database/sql"), nullType))
+			args = append(args, "&"+temp)
+			value = temp + "." + valueField
+			if !types.Identical(scanType, valueType) {
+				value = fmt.Sprintf("%s(%s)", f.typeString(scanType), value)
+			}
+		} else {
+			temp := "_conv" + target.name
+			scan.Vars = append(scan.Vars, fmt.Sprintf("var %s %s", temp, f.typeString(scanType)))
+			args = append(args, "&"+temp)
+			value = temp
+		}
+
+		assign := &ScanAssign{Target: target.expr, Value: value}
+		if scanConverter != nil {
+			assign.Value = fmt.Sprintf("%s(%s)", f.funcString(scanConverter), value)
+			assign.Err = f.convertSignature(scanConverter).Results().Len() == 2
+		}
+		scan.Assigns = append(scan.Assigns, assign)
+	}
+	scan.Args = strings.Join(args, ", ")
+	return scan, nil
+}
+
+//acceptNull return true if the type can be scanned from NULL directly,
+//e.g. pointers, sql.Scanner implementations like sql.NullString, []byte and interfaces
+func (f *functions) acceptNull(typ types.Type) bool {
+	switch typ := typ.Underlying().(type) {
+	case *types.Pointer, *types.Interface:
+		return true
+	case *types.Slice:
+		if elem, ok := unalias(typ.Elem()).(*types.Basic); ok && elem.Kind() == types.Byte {
+			return true
+		}
+	}
+	return f.hasMethod(typ, "Scan")
+}
+
+//nullTypeOf return the sql.Null* type, its value field and the value field type to scan a nullable column of typ,
+//the nullType is empty if unsupported
+func (f *functions) nullTypeOf(typ types.Type) (nullType, valueField string, valueType types.Type) {
+	if named, ok := unalias(typ).(*types.Named); ok {
+		obj := named.Obj()
+		if obj.Pkg() != nil && obj.Pkg().Path() == "time" && obj.Name() == "Time" {
+			return "NullTime", "Time", typ
+		}
+	}
+	basic, ok := typ.Underlying().(*types.Basic)
+	if !ok {
+		return
+	}
+	switch basic.Kind() {
+	case types.String:
+		return "NullString", "String", types.Typ[types.String]
+	case types.Bool:
+		return "NullBool", "Bool", types.Typ[types.Bool]
+	case types.Uint8:
+		return "NullByte", "Byte", types.Typ[types.Byte]
+	case types.Int16:
+		return "NullInt16", "Int16", types.Typ[types.Int16]
+	case types.Int32:
+		return "NullInt32", "Int32", types.Typ[types.Int32]
+	case types.Int, types.Int8, types.Int64, types.Uint, types.Uint16, types.Uint32, types.Uint64:
+		return "NullInt64", "Int64", types.Typ[types.Int64]
+	case types.Float32, types.Float64:
+		return "NullFloat64", "Float64", types.Typ[types.Float64]
+	}
+	return
+}
+
+//loadConverters register the converters of the options, then the funcs with MetaConverter in the generating package
+func (f *functions) loadConverters() error {
+	if f.converters != nil {
+		return nil
+	}
+	f.converters = map[string]*converter{}
+	funcs := make([]types.Object, 0, len(f.convertFuncs))
+	for _, funcName := range f.convertFuncs {
+		fn, err := f.lookupFunc(funcName)
+		if err != nil {
+			return err
+		}
+		funcs = append(funcs, fn)
+	}
+	funcs = append(funcs, f.metaParser.FilterByMeta(MetaConverter, f.pkgParser.Functions(f.pkgPath))...)
+	for _, fn := range funcs {
+		if err := f.registerConverter(fn); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+//lookupFunc return the func of the qualified name, e.g. github.com/shopspring/decimal.NewFromString
+func (f *functions) lookupFunc(funcName string) (types.Object, error) {
+	dot := strings.LastIndex(funcName, ".")
+	if dot <= 0 {
+		return nil, fmt.Errorf("converter must be a qualified func name,func=%s", funcName)
+	}
+	pkgPath, name := funcName[:dot], funcName[dot+1:]
+	pkg := f.pkgParser.Package(pkgPath)
+	if pkg == nil {
+		if err := f.pkgParser.Load(pkgPath); err != nil {
+			return nil, fmt.Errorf("load converter fail: %w,func=%s", err, funcName)
+		}
+		pkg = f.pkgParser.Package(pkgPath)
+	}
+	if pkg == nil || pkg.Types == nil {
+		return nil, fmt.Errorf("can not find converter package,func=%s", funcName)
+	}
+	fn, ok := pkg.Types.Scope().Lookup(name).(*types.Func)
+	if !ok {
+		return nil, fmt.Errorf("can not find converter func,func=%s", funcName)
+	}
+	return fn, nil
+}
+
+//registerConverter register fn as the scan converter of its result type if its param type is supported by the driver,
+//or as the value converter of its param type if its result type is supported by the driver
+func (f *functions) registerConverter(fn types.Object) error {
+	signature := f.convertSignature(fn)
+	if signature == nil {
+		return fmt.Errorf("converter must be func(T) R or func(T) (R, error),func=%s", fn.String())
+	}
+	paramType, resultType := signature.Params().At(0).Type(), signature.Results().At(0).Type()
+	paramDB, resultDB := f.isDBType(paramType), f.isDBType(resultType)
+	switch {
+	case paramDB && !resultDB:
+		f.converterOf(resultType).scan = fn
+	case resultDB && !paramDB:
+		f.converterOf(paramType).value = fn
+	default:
+		return fmt.Errorf("converter must convert between a field type and a type supported by the driver,func=%s",
+			fn.String())
+	}
+	return nil
+}
+
+//convertSignature return the signature of a converter func, nil if fn is not a converter
+func (f *functions) convertSignature(fn types.Object) *types.Signature {
+	signature, ok := fn.Type().(*types.Signature)
+	if !ok || signature.Recv() != nil || signature.Variadic() || signature.Params().Len() != 1 {
+		return nil
+	}
+	results := signature.Results()
+	switch {
+	case results.Len() == 1:
+		return signature
+	case results.Len() == 2 && types.Identical(results.At(1).Type(), types.Universe.Lookup("error").Type()):
+		return signature
+	}
+	return nil
+}
+
+func (f *functions) converterOf(fieldType types.Type) *converter {
+	key := types.TypeString(fieldType, nil)
+	fieldConverter := f.converters[key]
+	if fieldConverter == nil {
+		fieldConverter = &converter{}
+		f.converters[key] = fieldConverter
+	}
+	return fieldConverter
+}
+
+//converter return the converter of the field type, the funcs are nil if there is no converter
+func (f *functions) converter(fieldType types.Type) *converter {
+	if fieldConverter := f.converters[types.TypeString(fieldType, nil)]; fieldConverter != nil {
+		return fieldConverter
+	}
+	return &converter{}
+}
+
+//isDBType return true if the driver scans and binds the type directly,
+//e.g. the unnamed basic types, []byte, time.Time and sql.Null* types
+func (f *functions) isDBType(typ types.Type) bool {
+	switch typ := unalias(typ).(type) {
+	case *types.Basic:
+		return true
+	case *types.Slice:
+		elem, ok := unalias(typ.Elem()).(*types.Basic)
+		return ok && elem.Kind() == types.Byte
+	case *types.Named:
+		obj := typ.Obj()
+		if obj.Pkg() != nil && obj.Pkg().Path() == "time" && obj.Name() == "Time" {
+			return true
+		}
+		return f.hasMethod(typ, "Scan") && f.hasMethod(typ, "Value")
+	}
+	return false
+}
+
+//hasMethod return true if typ or *typ has the method
+func (f *functions) hasMethod(typ types.Type, name string) bool {
+	method, _, _ := types.LookupFieldOrMethod(types.NewPointer(typ), true, nil, name)
+	_, ok := method.(*types.Func)
+	return ok
+}
+
+//bindArg return the arg expr converted by the value converter of its type
+func (f *functions) bindArg(expr string, typ types.Type) string {
+	valueConverter := f.converter(typ).value
+	if valueConverter == nil {
+		return expr
+	}
+	if f.convertSignature(valueConverter).Results().Len() == 2 {
+		return fmt.Sprintf("%s.ValueFunc(%s, %s)",
+			f.importTracker.Import("github.com/gomelon/sqlmap/dao"), expr, f.funcString(valueConverter))
+	}
+	return fmt.Sprintf("%s(%s)", f.funcString(valueConverter), expr)
+}
+
+//bindFieldArg return the arg expr of the struct field, the json field is bound as a json text
+func (f *functions) bindFieldArg(expr string, columnField *columnField) string {
+	if columnField.hasOption("json") {
+		return fmt.Sprintf("%s.JSONValue(%s)", f.importTracker.Import("github.com/gomelon/sqlmap/dao"), expr)
+	}
+	return f.bindArg(expr, columnField.field.Type())
+}
+
+func (f *functions) funcString(fn types.Object) string {
+	if qualifier := f.importTracker.Import(fn.Pkg().Path()); len(qualifier) > 0 {
+		return qualifier + "." + fn.Name()
+	}
+	return fn.Name()
+}
+
+func (f *functions) typeString(typ types.Type) string {
+	return types.TypeString(typ, func(pkg *types.Package) string {
+		return f.importTracker.Import(pkg.Path())
+	})
+}
+
+//CallbackParam return the last param of a streaming select method which only returns an error,
+//it is a func(T) error called with every scanned row, nil if the method is not streaming
+func (f *functions) CallbackParam(method types.Object) types.Object {
+	params := f.pkgParser.Params(method)
+	if len(params) == 0 || len(f.pkgParser.Results(method)) != 1 {
+		return nil
+	}
+	lastParam := params[len(params)-1]
+	signature, ok := unalias(lastParam.Type()).(*types.Signature)
+	if !ok || signature.Params().Len() != 1 || signature.Results().Len() != 1 ||
+		!types.Identical(signature.Results().At(0).Type(), types.Universe.Lookup("error").Type()) {
+		return nil
+	}
+	return lastParam
+}
+
+//RowsItem return T of the iterator result *dao.Rows[T], nil if typ is not the iterator
+func (f *functions) RowsItem(typ types.Type) types.Type {
+	return f.daoTypeArg(typ, "Rows")
+}
+
+//PageResult return PageResultPage of *dao.Page[T], PageResultSlice of *dao.Slice[T], empty for the others
+func (f *functions) PageResult(typ types.Type) string {
+	for _, pageResult := range []string{PageResultPage, PageResultSlice} {
+		if f.daoTypeArg(typ, pageResult) != nil {
+			return pageResult
+		}
+	}
+	return ""
+}
+
+//daoTypeArg return T of *dao.<name>[T], nil if typ is not the pointer of the dao generic type
+func (f *functions) daoTypeArg(typ types.Type, name string) types.Type {
+	pointer, ok := unalias(typ).(*types.Pointer)
+	if !ok {
+		return nil
+	}
+	named, ok := unalias(pointer.Elem()).(*types.Named)
+	if !ok || named.TypeArgs().Len() != 1 {
+		return nil
+	}
+	obj := named.Obj()
+	if obj.Pkg() == nil || obj.Pkg().Path() != "github.com/gomelon/sqlmap/dao" || obj.Name() != name {
+		return nil
+	}
+	return named.TypeArgs().At(0)
+}
+
+//PagerParam return the param to query a page of rows, it has the methods Page, PageSize and Offset return int,
+//e.g. query.Pager, nil if there is no pager param
+func (f *functions) PagerParam(method types.Object) types.Object {
+	for _, param := range f.pkgParser.Params(method) {
+		if f.intMethod(param.Type(), "Page") && f.intMethod(param.Type(), "PageSize") &&
+			f.intMethod(param.Type(), "Offset") {
+			return param
+		}
+	}
+	return nil
+}
+
+//intMethod return true if typ has the method func() int
+func (f *functions) intMethod(typ types.Type, name string) bool {
+	method, _, _ := types.LookupFieldOrMethod(typ, false, nil, name)
+	fn, ok := method.(*types.Func)
+	if !ok {
+		return false
+	}
+	signature := fn.Type().(*types.Signature)
+	return signature.Params().Len() == 0 && signature.Results().Len() == 1 &&
+		types.Identical(signature.Results().At(0).Type(), types.Typ[types.Int])
+}
+
+//BuildPaging append the LIMIT clause of the dialect to the sql for the method returning *dao.Page or *dao.Slice,
+//the Page result counts the rows of all the pages by wrapping the sql as a derived table
+func (f *functions) BuildPaging(method types.Object, mapper *Mapper, sel *Select, sql string) (
+	paging *Paging, err error) {
+
+	pagerParam := f.PagerParam(method)
+	pageResult := f.PageResult(f.pkgParser.FirstResult(method).Type())
+	if pagerParam == nil || len(pageResult) == 0 {
+		err = fmt.Errorf("paging method must have a pager param and return *dao.Page or *dao.Slice,method=%s",
+			method.String())
+		return
+	}
+
+	dialect := f.Dialect(mapper)
+	numArgs, err := f.numQueryArgs(method, dialect, sel)
+	if err != nil {
+		return
+	}
+
+	//the offset and limit are bound in the order they appear in the LIMIT clause of the dialect
+	const offsetMarker, limitMarker = "\x00offset", "\x00limit"
+	limit := f.engine(mapper).BuildLimit(offsetMarker, limitMarker)
+	offsetArg := pagerParam.Name() + ".Offset()"
+	limitArg := pagerParam.Name() + ".PageSize()"
+	if pageResult == PageResultSlice {
+		//query one more row to know whether there is a next page
+		limitArg += "+1"
+	}
+	args := []string{offsetArg, limitArg}
+	markers := []string{offsetMarker, limitMarker}
+	if strings.Index(limit, limitMarker) < strings.Index(limit, offsetMarker) {
+		args[0], args[1] = args[1], args[0]
+		markers[0], markers[1] = markers[1], markers[0]
+	}
+	for i, marker := range markers {
+		bindVar, bindErr := f.bindVar(dialect, numArgs+i+1)
+		if bindErr != nil {
+			err = fmt.Errorf("%w,method=%s", bindErr, method.String())
+			return
+		}
+		limit = strings.Replace(limit, marker, bindVar, 1)
+	}
+
+	paging = &Paging{
+		Param:  pagerParam,
+		Result: pageResult,
+		SQL:    sql + " " + limit,
+		Args:   args[0] + ", " + args[1] + ",",
+	}
+	if pageResult == PageResultPage {
+		paging.CountSQL = "SELECT COUNT(*) FROM (" + sql + ") AS _page"
+	}
+	return
+}
+
+//numQueryArgs return the number of the args bound to the select query
+func (f *functions) numQueryArgs(method types.Object, dialect string, sel *Select) (numArgs int, err error) {
+	_, queryNames, err := f.compileNamedQuery(sel.Query, dialect)
+	if err != nil {
+		err = fmt.Errorf("compile named query fail: %w,method=[%s],sql=%s", err, method.String(), sel.Query)
+		return
+	}
+	numArgs = len(queryNames)
+	if numArgs == 0 {
+		numArgs = len(f.methodParamsWithoutCtx(method))
+	}
+	return
+}
+
+//CursorResult return T of *dao.CursorPage[T], nil if typ is not the cursor page
+func (f *functions) CursorResult(typ types.Type) types.Type {
+	return f.daoTypeArg(typ, "CursorPage")
+}
+
+//CursorParam return the param of dao.CursorRequest or *dao.CursorRequest, nil if there is no cursor param
+func (f *functions) CursorParam(method types.Object) types.Object {
+	for _, param := range f.pkgParser.Params(method) {
+		typ := unalias(param.Type())
+		if pointer, ok := typ.(*types.Pointer); ok {
+			typ = unalias(pointer.Elem())
+		}
+		named, ok := typ.(*types.Named)
+		if !ok {
+			continue
+		}
+		obj := named.Obj()
+		if obj.Pkg() != nil && obj.Pkg().Path() == "github.com/gomelon/sqlmap/dao" && obj.Name() == "CursorRequest" {
+			return param
+		}
+	}
+	return nil
+}
+
+//BuildCursorPaging rewrite the sql to query the rows after the keyset of Select.Cursor
+//for the method returning *dao.CursorPage, e.g.
+//    SELECT id, name FROM user WHERE gender = ? AND id > ? ORDER BY id LIMIT ?
+//the keyset condition is omitted for the first page, the ORDER BY clause of the sql is replaced by the keyset,
+//one more row than the limit is queried to know whether there is a next page
+func (f *functions) BuildCursorPaging(method types.Object, mapper *Mapper, sel *Select, sql string) (
+	paging *CursorPaging, err error) {
+
+	cursorParam := f.CursorParam(method)
+	if cursorParam == nil || len(sel.Cursor) == 0 {
+		err = fmt.Errorf("cursor paging method must have a dao.CursorRequest param and a Cursor,method=%s",
+			method.String())
+		return
+	}
+	keyset, err := f.cursorKeyset(sel.Cursor)
+	if err != nil {
+		err = fmt.Errorf("%w,method=%s", err, method.String())
+		return
+	}
+
+	dialect := f.Dialect(mapper)
+	numArgs, err := f.numQueryArgs(method, dialect, sel)
+	if err != nil {
+		return
+	}
+	sqlParser, err := parser.New(dialect, sql)
+	if err != nil {
+		err = fmt.Errorf("parse sql fail: %w, method=[%s],sql=%s", err, method.String(), sql)
+		return
+	}
+	if keyset.Limit, err = f.bindVar(dialect, numArgs+1); err != nil {
+		err = fmt.Errorf("%w,method=%s", err, method.String())
+		return
+	}
+	firstSQL, err := sqlParser.Keyset(keyset)
+	if err != nil {
+		err = fmt.Errorf("parse sql fail: %w, method=[%s],sql=%s", err, method.String(), sql)
+		return
+	}
+	for i := range keyset.Columns {
+		bindVar, _ := f.bindVar(dialect, numArgs+i+1)
+		keyset.After = append(keyset.After, bindVar)
+	}
+	keyset.Limit, _ = f.bindVar(dialect, numArgs+len(keyset.Columns)+1)
+	afterSQL, err := sqlParser.Keyset(keyset)
+	if err != nil {
+		err = fmt.Errorf("parse sql fail: %w, method=[%s],sql=%s", err, method.String(), sql)
+		return
+	}
+
+	paging = &CursorPaging{Param: cursorParam, SQL: firstSQL, AfterSQL: afterSQL}
+	itemType := f.ItemType(method)
+	rowStruct := f.nestedStruct(itemType)
+	var decodes, keysetValues []string
+	for _, column := range keyset.Columns {
+		name, value, typ := "_after", "_item", itemType
+		if rowStruct != nil {
+			columnField := f.findColumnField(f.scanColumnFields(rowStruct), column)
+			if columnField == nil {
+				err = fmt.Errorf("can not find field of cursor column %s in %s,method=%s",
+					column, itemType.String(), method.String())
+				return
+			}
+			name += strings.ReplaceAll(columnField.fieldPath(), ".", "")
+			value += "." + columnField.fieldPath()
+			typ = columnField.field.Type()
+		} else if len(keyset.Columns) > 1 {
+			err = fmt.Errorf("cursor of the single column result must be the column,method=%s", method.String())
+			return
+		}
+		paging.Vars = append(paging.Vars, "var "+name+" "+f.typeString(typ))
+		paging.Args += f.bindArg(name, typ) + ","
+		decodes = append(decodes, "&"+name)
+		keysetValues = append(keysetValues, value)
+	}
+	paging.Decode = strings.Join(decodes, ", ")
+	paging.Keyset = strings.Join(keysetValues, ", ")
+	return
+}
+
+//cursorKeyset parse the columns and the direction of Select.Cursor, e.g. created_at DESC,id DESC,
+//the keyset is compared as a row value, so the columns in mixed directions are rejected
+func (f *functions) cursorKeyset(cursor string) (keyset *parser.Keyset, err error) {
+	keyset = &parser.Keyset{}
+	for i, column := range f.splitColumns(cursor) {
+		fields := strings.Fields(column)
+		desc := len(fields) == 2 && strings.EqualFold(fields[1], "desc")
+		if len(fields) > 2 || (len(fields) == 2 && !desc && !strings.EqualFold(fields[1], "asc")) {
+			return nil, fmt.Errorf("invalid cursor column %s", column)
+		}
+		if i > 0 && desc != keyset.Desc {
+			return nil, fmt.Errorf("cursor columns must be ordered in the same direction, cursor=%s", cursor)
+		}
+		keyset.Columns = append(keyset.Columns, fields[0])
+		keyset.Desc = desc
+	}
+	return
+}
+
+//SortParam return the param of []*query.Sort to sort the rows at runtime, nil if there is no sort param
+func (f *functions) SortParam(method types.Object) types.Object {
+	for _, param := range f.pkgParser.Params(method) {
+		slice, ok := unalias(param.Type()).(*types.Slice)
+		if !ok {
+			continue
+		}
+		pointer, ok := unalias(slice.Elem()).(*types.Pointer)
+		if !ok {
+			continue
+		}
+		named, ok := unalias(pointer.Elem()).(*types.Named)
+		if !ok {
+			continue
+		}
+		obj := named.Obj()
+		if obj.Pkg() != nil && obj.Pkg().Path() == "github.com/gomelon/melon/data/query" && obj.Name() == "Sort" {
+			return param
+		}
+	}
+	return nil
+}
+
+//checkSortParam the sort param is only supported by the methods returning multiple rows without a cursor
+func (f *functions) checkSortParam(method types.Object, sel *Select) error {
+	if f.SortParam(method) == nil {
+		return nil
+	}
+	if len(sel.Cursor) > 0 {
+		return fmt.Errorf("sort param can not be used with the cursor,method=%s", method.String())
+	}
+	if f.CallbackParam(method) != nil {
+		return nil
+	}
+	resultType := f.pkgParser.FirstResult(method).Type()
+	switch unalias(resultType).(type) {
+	case *types.Slice:
+		return nil
+	case *types.Map:
+		if !f.RowMap(resultType) {
+			return nil
+		}
+	}
+	if f.RowsItem(resultType) != nil || len(f.PageResult(resultType)) > 0 {
+		return nil
+	}
+	return fmt.Errorf("sort param is only supported by the methods returning multiple rows,method=%s",
+		method.String())
+}
+
+//BuildSorting split the sql around its ORDER BY clause, which is replaced by the sort param if it is not empty,
+//the ORDER BY clause is appended if the sql has none,
+//the sort fields are allowed to be the selected columns of the result and the field names of them
+func (f *functions) BuildSorting(method types.Object, mapper *Mapper, sql string) (sorting *Sorting, err error) {
+	sortParam := f.SortParam(method)
+	if sortParam == nil {
+		return
+	}
+	sqlParser, err := parser.New(f.Dialect(mapper), sql)
+	if err != nil {
+		err = fmt.Errorf("parse sql fail: %w, method=[%s],sql=%s", err, method.String(), sql)
+		return
+	}
+	columns, err := sqlParser.SelectColumns()
+	if err != nil {
+		err = fmt.Errorf("parse sql fail: %w, method=[%s],sql=%s", err, method.String(), sql)
+		return
+	}
+	head, _, tail, err := parser.SplitOrderBy(sql)
+	if err != nil {
+		err = fmt.Errorf("parse sql fail: %w, method=[%s],sql=%s", err, method.String(), sql)
+		return
+	}
+
+	dialectEngine := f.engine(mapper)
+	sorting = &Sorting{Param: sortParam, Head: head + " ", Columns: make(map[string]string)}
+	if len(tail) > 0 {
+		sorting.Tail = " " + tail
+	}
+	itemType := f.ItemType(method)
+	rowStruct := f.nestedStruct(itemType)
+	var columnFields []*columnField
+	if rowStruct != nil {
+		columnFields = f.scanColumnFields(rowStruct)
+	}
+	for _, column := range columns {
+		if column.Alias == "*" {
+			continue
+		}
+		//the selected column is sorted by its expression, the alias of a joined column may be ambiguous,
+		//the alias of a computed column is sorted by the escaped alias
+		expr, exprErr := sqlParser.ColumnExpr(column.Alias)
+		if exprErr != nil {
+			expr = dialectEngine.Escape(column.Alias)
+		}
+		sorting.Columns[column.Alias] = expr
+		if columnField := f.findColumnField(columnFields, column.Alias); columnField != nil {
+			sorting.Columns[columnField.fieldPath()] = expr
+		}
+	}
+	if len(sorting.Columns) == 0 {
+		err = fmt.Errorf("parse sql fail: no selected column to sort, method=[%s],sql=%s", method.String(), sql)
+	}
+	return
+}
+
+//bindVar return the n-th bindvar of the dialect, it starts from 1
+func (f *functions) bindVar(dialect string, n int) (string, error) {
+	switch sqlx.BindType(dialect) {
+	case sqlx.QUESTION:
+		return "?", nil
+	case sqlx.DOLLAR:
+		return "$" + strconv.Itoa(n), nil
+	default:
+		return "", fmt.Errorf("unsupported bindvar of dialect,dialect=%s", dialect)
+	}
+}
+
+//ItemType return the type of a row scanned by the select method,
+//e.g. *User of the results *User, []*User, map[int64]*User, *dao.Rows[*User], *dao.Page[*User], *dao.CursorPage[*User]
+//and the callback param func(*User) error
+func (f *functions) ItemType(method types.Object) types.Type {
+	if callbackParam := f.CallbackParam(method); callbackParam != nil {
+		return unalias(callbackParam.Type()).(*types.Signature).Params().At(0).Type()
+	}
+	itemType := f.pkgParser.FirstResult(method).Type()
+	for _, name := range []string{"Rows", PageResultPage, PageResultSlice, "CursorPage"} {
+		if typeArg := f.daoTypeArg(itemType, name); typeArg != nil {
+			return typeArg
+		}
+	}
+	switch resultType := unalias(itemType).(type) {
+	case *types.Slice:
+		return resultType.Elem()
+	case *types.Map:
+		if !f.RowMap(resultType) {
+			return resultType.Elem()
+		}
+	}
+	return itemType
+}
+
+//RowMap return true if typ is map[string]any, the row is scanned into it by the column names
+func (f *functions) RowMap(typ types.Type) bool {
+	mapType, ok := unalias(typ).(*types.Map)
+	if !ok {
+		return false
+	}
+	key, ok := unalias(mapType.Key()).(*types.Basic)
+	if !ok || key.Kind() != types.String {
+		return false
+	}
+	elem, ok := unalias(mapType.Elem()).(*types.Interface)
+	return ok && elem.Empty()
+}
+
+//MapKey return the expr of the _item field as the key of the map result, the field is mapped by Select.MapKey
+func (f *functions) MapKey(method types.Object, mapper *Mapper, sql string, sel *Select) (key string, err error) {
+	mapType, ok := unalias(f.pkgParser.FirstResult(method).Type()).(*types.Map)
+	if !ok {
+		err = fmt.Errorf("map key is only supported by the map result,method=%s", method.String())
+		return
+	}
+	if len(sel.MapKey) == 0 {
+		err = fmt.Errorf("map result must be map[string]any or has a MapKey,method=%s", method.String())
+		return
+	}
+	rowStruct := f.nestedStruct(mapType.Elem())
+	if rowStruct == nil {
+		err = fmt.Errorf("the value of map result must be a struct,method=%s", method.String())
+		return
+	}
+
+	sqlParser, err := parser.New(f.Dialect(mapper), sql)
+	if err != nil {
+		err = fmt.Errorf("parse sql fail: %w, method=[%s],sql=%s", err, method.String(), sql)
+		return
+	}
+	columns, err := sqlParser.SelectColumns()
+	if err != nil {
+		err = fmt.Errorf("parse sql fail: %w, method=[%s],sql=%s", err, method.String(), sql)
+		return
+	}
+	selected := false
+	for _, column := range columns {
+		if column.Alias == "*" || strings.EqualFold(column.Alias, sel.MapKey) {
+			selected = true
+			break
+		}
+	}
+	if !selected {
+		err = fmt.Errorf("parse sql fail: map key %s is not selected, method=[%s],sql=%s",
+			sel.MapKey, method.String(), sql)
+		return
+	}
+
+	columnField := f.findColumnField(f.scanColumnFields(rowStruct), sel.MapKey)
+	if columnField == nil {
+		err = fmt.Errorf("can not find field of map key %s in %s,method=%s",
+			sel.MapKey, mapType.Elem().String(), method.String())
+		return
+	}
+	key = "_item." + columnField.fieldPath()
+	fieldType := columnField.field.Type()
+	switch {
+	case types.AssignableTo(fieldType, mapType.Key()):
+	case types.ConvertibleTo(fieldType, mapType.Key()):
+		key = fmt.Sprintf("%s(%s)", f.typeString(mapType.Key()), key)
+	default:
+		err = fmt.Errorf("map key type %s is not convertible from field %s of %s,method=%s",
+			mapType.Key().String(), columnField.fieldPath(), fieldType.String(), method.String())
+	}
+	return
+}
+
+//Returning return true if the sql returns the generated columns by a RETURNING clause,
+//then it must be executed by QueryRow instead of Exec
+func (f *functions) Returning(method types.Object, mapper *Mapper, sql string) (bool, error) {
+	sqlParser, err := parser.New(f.Dialect(mapper), sql)
+	if err != nil {
+		return false, fmt.Errorf("parse sql fail: %w, method=[%s],sql=%s", err, method.String(), sql)
+	}
+	columns, err := sqlParser.ReturningColumns()
+	if err != nil {
+		return false, fmt.Errorf("parse sql fail: %w, method=[%s],sql=%s", err, method.String(), sql)
+	}
+	if len(columns) > 1 {
+		return false, fmt.Errorf("parse sql fail: only the generated id can be returned, method=[%s],sql=%s",
+			method.String(), sql)
+	}
+	return len(columns) == 1, nil
+}
+
+func (f *functions) QueryArgs(method types.Object, mapper *Mapper, querier Querier) (nameArgsStr string, err error) {
+	dialect := f.Dialect(mapper)
+	originQuery := querier.GetQuery()
+	_, queryNames, err := f.compileNamedQuery(originQuery, dialect)
+	if err != nil {
+		return
+	}
+	//the args of the query built at runtime are built with the sql, see DynamicSQL
+	if f.runtimeSQL(method, originQuery) {
+		nameArgsStr = "_args..."
+		return
+	}
+
+	toArgMethodParams := f.methodParamsWithoutCtx(method)
+	if len(queryNames) == 0 {
+		nameArgsStr = f.positionArgsStr(toArgMethodParams)
+		return
+	}
+
+	nameArgsStr, err = f.nameArgsStr(queryNames, toArgMethodParams)
+	if err != nil {
+		err = fmt.Errorf("parse sql fail: %w, method=[%s],sql=%s", err, method.String(), originQuery)
+	}
+	return
+}
+
+//ExecResult return how the result of an exec statement is returned by method,
+//it is decided by the first result of the method:
+//    none:                     ExecResultNone
+//    int64 with LastInsertId:  ExecResultLastInsertId, declared by +sqlmap.Insert or +sqlmap.Upsert LastInsertId=true
+//    int64:                    ExecResultRowsAffected
+//    same type as a param:     ExecResultEntity, the param is returned with the generated id filled
+func (f *functions) ExecResult(method types.Object) (execResult string, err error) {
+	results := f.pkgParser.Results(method)
+	if len(results) <= 1 {
+		execResult = ExecResultNone
+		return
+	}
+
+	firstResult := results[0]
+	switch resultType := unalias(firstResult.Type()).(type) {
+	case *types.Basic:
+		if resultType.Kind() != types.Int64 {
+			err = fmt.Errorf("exec method result must be int64 but %s,method=%s",
+				resultType.String(), method.String())
+			return
+		}
+		var lastInsertId bool
+		lastInsertId, err = f.lastInsertId(method)
+		if err != nil {
+			return
+		}
+		if lastInsertId {
+			execResult = ExecResultLastInsertId
+		} else {
+			execResult = ExecResultRowsAffected
+		}
+	default:
+		if f.EntityParam(method) == nil {
+			err = fmt.Errorf("exec method result must be int64 or the same type as a param,method=%s",
+				method.String())
+			return
+		}
+		execResult = ExecResultEntity
+	}
+	return
+}
+
+//lastInsertId return true if the insert or upsert annotation of method declares LastInsertId
+func (f *functions) lastInsertId(method types.Object) (bool, error) {
+	metaName, group, err := f.subjectMeta(method)
+	if err != nil || len(group) == 0 {
+		return false, err
+	}
+	switch metaName {
+	case MetaInsert:
+		insertMeta := &Insert{}
+		err = group[0].MapTo(insertMeta)
+		return insertMeta.LastInsertId, err
+	case MetaUpsert:
+		upsertMeta := &Upsert{}
+		err = group[0].MapTo(upsertMeta)
+		return upsertMeta.LastInsertId, err
+	}
+	return false, nil
+}
+
+//EntityParam return the param which has the same type as the first result of method
+func (f *functions) EntityParam(method types.Object) types.Object {
+	firstResult := f.pkgParser.FirstResult(method)
+	if firstResult == nil {
+		return nil
+	}
+	for _, param := range f.methodParamsWithoutCtx(method) {
+		if types.Identical(param.Type(), firstResult.Type()) {
+			return param
+		}
+	}
+	return nil
+}
+
+//IdField return the id field of the entity param, the generated id will fill into it
+func (f *functions) IdField(method types.Object) (idField *types.Var, err error) {
+	entityParam := f.EntityParam(method)
+	if entityParam == nil {
+		err = fmt.Errorf("can not find entity param,method=%s", method.String())
+		return
+	}
+	entityStruct, ok := f.underlyingType(entityParam.Type()).(*types.Struct)
+	if !ok {
+		err = fmt.Errorf("entity param must be a struct,method=%s", method.String())
+		return
+	}
+	idField = f.idFieldOf(entityStruct)
+	if idField == nil {
+		err = fmt.Errorf("can not find id field in entity %s,method=%s", entityParam.Type().String(), method.String())
+	}
+	return
+}
+
+//MultipleLines quote the sql as a go string literal per line,
+//it overrides the one of meta which does not escape the double-quoted identifiers
+func (f *functions) MultipleLines(sql string) string {
+	lines := strings.Split(sql, "\n")
+	for i, line := range lines {
+		if i < len(lines)-1 {
+			line += "\n"
+		}
+		lines[i] = strconv.Quote(line)
+	}
+	return strings.Join(lines, "+\n")
+}
+
+func (f *functions) Dialect(mapper *Mapper) string {
+	if dialectEngine := f.engine(mapper); dialectEngine != nil {
+		return dialectEngine.Dialect()
+	}
+	return strings.ToLower(mapper.Dialect)
+}
+
+func (f *functions) positionArgsStr(toArgsMethodParams []types.Object) string {
+	if len(toArgsMethodParams) == 0 {
+		return ""
+	}
+	argsBuilder := strings.Builder{}
+	argsBuilder.Grow(64)
+	for _, param := range toArgsMethodParams {
+		argsBuilder.WriteString(f.bindArg(param.Name(), param.Type()))
+		argsBuilder.WriteRune(',')
+
+	}
+	return argsBuilder.String()
+}
+
+//nameArgsStr return the args of the query names in the order of the query,
+//every query name must bind to a param or a struct param field, and every param must be bound,
+//so the order of the params does not matter and a param can be bound by multiple query names
+func (f *functions) nameArgsStr(queryNames []string, toArgsMethodParams []types.Object) (string, error) {
+	usedParams := make(map[types.Object]bool, len(toArgsMethodParams))
+	argsBuilder := strings.Builder{}
+	argsBuilder.Grow(64)
+	for _, queryName := range queryNames {
+		arg, param, err := f.namedArg(queryName, toArgsMethodParams)
+		if err != nil {
+			return "", err
+		}
+		usedParams[param] = true
+		argsBuilder.WriteString(arg)
+		argsBuilder.WriteRune(',')
+	}
+	if err := f.checkUnusedParams(toArgsMethodParams, usedParams); err != nil {
+		return "", err
+	}
+	return argsBuilder.String(), nil
+}
+
+//checkUnusedParams return an error if some params are not bound by the query, e.g. the query name is misspelled
+func (f *functions) checkUnusedParams(toArgsMethodParams []types.Object, usedParams map[types.Object]bool) error {
+	var unusedParams []string
+	for _, param := range toArgsMethodParams {
+		if !usedParams[param] {
+			unusedParams = append(unusedParams, param.Name())
+		}
+	}
+	if len(unusedParams) > 0 {
+		return fmt.Errorf("params %v are not bound by any named arg", unusedParams)
+	}
+	return nil
+}
+
+//namedArg return the arg expr of the param or the struct param field which the query name bind to,
+//and the param it belongs to
+func (f *functions) namedArg(queryName string, toArgsMethodParams []types.Object) (
+	arg string, param types.Object, err error) {
+
+	for _, param = range toArgsMethodParams {
+		if param.Name() == queryName {
+			arg = f.bindArg(queryName, param.Type())
+			return
+		}
+	}
+	param, columnField, err := f.structField(queryName, toArgsMethodParams)
+	if err != nil {
+		return
+	}
+	arg = f.bindFieldArg(param.Name()+"."+columnField.fieldPath(), columnField)
+	return
+}
+
+//structField find the struct param and its field which the query name bind to,
+//the query name can be the field name or the column name of the field,
+//and it can be qualified by the param name, e.g. user.Name, filter.Address.City or filter.addr__city,
+//the unqualified query name must not be the field of multiple struct params
+func (f *functions) structField(queryName string, toArgsMethodParams []types.Object) (
+	param types.Object, columnField *columnField, err error) {
+
+	fieldName := queryName
+	paramName, qualifiedName, qualified := strings.Cut(queryName, ".")
+	if qualified {
+		fieldName = qualifiedName
+	}
+	for _, methodParam := range toArgsMethodParams {
+		if qualified && methodParam.Name() != paramName {
+			continue
+		}
+		paramStruct := f.nestedStruct(methodParam.Type())
+		if paramStruct == nil {
+			continue
+		}
+		for _, paramField := range f.scanColumnFields(paramStruct) {
+			if paramField.fieldPath() != fieldName && paramField.column != fieldName {
+				continue
+			}
+			if param != nil {
+				err = fmt.Errorf("named arg %s is ambiguous, it is the field of both %s and %s, "+
+					"qualify it by the param name, e.g. %s.%s",
+					queryName, param.Name(), methodParam.Name(), param.Name(), queryName)
+				return nil, nil, err
+			}
+			param, columnField = methodParam, paramField
+			break
+		}
+	}
+	if param == nil {
+		err = fmt.Errorf("can not find param or struct param field for named arg %s", queryName)
+	}
+	return
+}
+
+//columnFields return the exported fields of the struct and the columns they map to,
+//the column is the name of the db tag, or the snake case of the field name if the name is empty,
+//the field is ignored if the db tag is "-", e.g.
+//    UserID    int64     `db:"user_id,pk"`
+//    CreatedAt time.Time `db:",omitempty"`
+//    Cache     string    `db:"-"`
+//the omitempty field is left out of the derived insert and entity update if it is zero,
+//so that the column keeps its default or its value, it is always bound by the batch insert and the upsert
+//the fields of the embedded structs are flattened as the promoted fields,
+//the nested struct fields are excluded, they are only mapped by scanColumnFields
+func (f *functions) columnFields(structType *types.Struct) []*columnField {
+	return f.structColumnFields(structType, false)
+}
+
+//scanColumnFields return the column fields to scan a query result into,
+//besides columnFields, the fields of the nested structs are mapped by the columns prefixed with the nested field, e.g.
+//    a.phone AS address__phone -> Address.Phone
+func (f *functions) scanColumnFields(structType *types.Struct) []*columnField {
+	return f.structColumnFields(structType, true)
+}
+
+func (f *functions) structColumnFields(structType *types.Struct, withNested bool) []*columnField {
+	numFields := structType.NumFields()
+	columnFields := make([]*columnField, 0, numFields)
+	var embeddedStructs []*types.Struct
+	fieldNames := make(map[string]bool, numFields)
+	for i := 0; i < numFields; i++ {
+		field := structType.Field(i)
+		column, options := f.parseDBTag(structType.Tag(i))
+		if column == "-" && len(options) == 0 {
+			continue
+		}
+		var nestedStruct *types.Struct
+		//the json field is mapped to a single column whatever its type is
+		if !containsString(options, "json") {
+			nestedStruct = f.nestedStruct(field.Type())
+		}
+		if field.Anonymous() {
+			//the fields of the embedded pointer can not be scanned before it is allocated
+			if _, ok := unalias(field.Type()).(*types.Pointer); !ok && nestedStruct != nil {
+				embeddedStructs = append(embeddedStructs, nestedStruct)
+				continue
+			}
+		}
+		if !field.Exported() {
+			continue
+		}
+		fieldNames[field.Name()] = true
+		tagged := len(column) > 0
+		if !tagged {
+			column = xstrings.ToSnakeCase(field.Name())
+		}
+		if nestedStruct == nil {
+			columnFields = append(columnFields, &columnField{
+				field:   field,
+				column:  column,
+				tagged:  tagged,
+				options: options,
+			})
+			continue
+		}
+		if _, ok := unalias(field.Type()).(*types.Pointer); ok || !withNested {
+			continue
+		}
+		for _, nestedColumnField := range f.structColumnFields(nestedStruct, withNested) {
+			columnFields = append(columnFields, &columnField{
+				field:   nestedColumnField.field,
+				path:    field.Name() + "." + nestedColumnField.fieldPath(),
+				column:  column + "__" + nestedColumnField.column,
+				options: nestedColumnField.options,
+			})
+		}
+	}
+	//the fields of the outer struct shadow the promoted fields of the embedded structs
+	for _, embeddedStruct := range embeddedStructs {
+		for _, embeddedColumnField := range f.structColumnFields(embeddedStruct, withNested) {
+			name := strings.SplitN(embeddedColumnField.fieldPath(), ".", 2)[0]
+			if fieldNames[name] {
+				continue
+			}
+			fieldNames[name] = true
+			columnFields = append(columnFields, embeddedColumnField)
+		}
+	}
+	return columnFields
+}
+
+//underlyingType return the underlying type of typ, or of the elem of the pointer, slice or map type recursively,
+//e.g. the struct of *User, []*User and map[int64]*User, the aliases are resolved as the types they denote
+func (f *functions) underlyingType(typ types.Type) types.Type {
+	switch typ := unalias(typ).(type) {
+	case *types.Named:
+		return f.underlyingType(typ.Underlying())
+	case *types.Pointer:
+		return f.underlyingType(typ.Elem())
+	case *types.Slice:
+		return f.underlyingType(typ.Elem())
+	case *types.Map:
+		return f.underlyingType(typ.Elem())
+	default:
+		return typ
+	}
+}
+
+//nestedStruct return the struct of the field type which is mapped to multiple columns,
+//nil if the field type is mapped to a single column, e.g. time.Time and the sql.Scanner implementations
+func (f *functions) nestedStruct(fieldType types.Type) *types.Struct {
+	fieldType = unalias(fieldType)
+	if pointer, ok := fieldType.(*types.Pointer); ok {
+		fieldType = unalias(pointer.Elem())
+	}
+	structType, ok := fieldType.Underlying().(*types.Struct)
+	if !ok {
+		return nil
+	}
+	if named, ok := fieldType.(*types.Named); ok {
+		obj := named.Obj()
+		if obj.Pkg() != nil && obj.Pkg().Path() == "time" && obj.Name() == "Time" {
+			return nil
+		}
+	}
+	if method, _, _ := types.LookupFieldOrMethod(types.NewPointer(fieldType), true, nil, "Scan"); method != nil {
+		if _, ok := method.(*types.Func); ok {
+			return nil
+		}
+	}
+	return structType
+}
+
+//parseDBTag return the column name and the options of the db tag
+func (f *functions) parseDBTag(tag string) (column string, options []string) {
+	dbTag, ok := reflect.StructTag(tag).Lookup("db")
+	if !ok {
+		return
+	}
+	parts := strings.Split(dbTag, ",")
+	column = strings.TrimSpace(parts[0])
+	for _, option := range parts[1:] {
+		option = strings.TrimSpace(option)
+		if len(option) > 0 {
+			options = append(options, option)
+		}
+	}
+	return
+}
+
+//idFieldOf return the auto increment primary key field of the struct, nil if not found,
+//it is the field tagged pk, or the field whose column or name is id
+func (f *functions) idFieldOf(structType *types.Struct) *types.Var {
+	columnFields := f.columnFields(structType)
+	for _, columnField := range columnFields {
+		if columnField.hasOption("pk") {
+			return columnField.field
+		}
+	}
+	for _, columnField := range columnFields {
+		if strings.EqualFold(columnField.column, "id") || strings.EqualFold(columnField.field.Name(), "id") {
+			return columnField.field
+		}
+	}
+	return nil
+}
+
+//insertColumnFields return the column fields to insert, the auto increment id field is excluded
+func (f *functions) insertColumnFields(structType *types.Struct) []*columnField {
+	idField := f.idFieldOf(structType)
+	columnFields := f.columnFields(structType)
+	insertColumnFields := make([]*columnField, 0, len(columnFields))
+	for _, columnField := range columnFields {
+		if columnField.field == idField {
+			continue
+		}
+		insertColumnFields = append(insertColumnFields, columnField)
+	}
+	return insertColumnFields
+}
+
+//batchRowPlaceholders return the go expression of a row's placeholders in a batch insert,
+//the row index variable is _i
+func (f *functions) batchRowPlaceholders(dialect string, numColumns int) (string, error) {
+	var prefix string
+	switch sqlx.BindType(dialect) {
+	case sqlx.QUESTION:
+		return "\"(" + strings.TrimSuffix(strings.Repeat("?, ", numColumns), ", ") + ")\"", nil
+	case sqlx.DOLLAR:
+		prefix = "$"
+	case sqlx.AT:
+		prefix = "@p"
+	default:
+		return "", fmt.Errorf("unsupported batch insert dialect,dialect=%s", dialect)
+	}
+	placeholders := make([]string, 0, numColumns)
+	for i := 1; i <= numColumns; i++ {
+		placeholders = append(placeholders, fmt.Sprintf("%s\" + strconv.Itoa(_i*%d+%d)", prefix, numColumns, i))
+	}
+	return "\"(" + strings.Join(placeholders, " + \", ") + " + \")\"", nil
+}
+
+//splitColumns split the comma separated columns
+func (f *functions) splitColumns(columnsStr string) []string {
+	var columns []string
+	for _, column := range strings.Split(columnsStr, ",") {
+		column = strings.TrimSpace(column)
+		if len(column) == 0 {
+			continue
+		}
+		columns = append(columns, column)
+	}
+	return columns
+}
+
+//BatchParam return the first param which is a slice of struct or a slice of pointer to struct
+func (f *functions) BatchParam(method types.Object) types.Object {
+	for _, param := range f.methodParamsWithoutCtx(method) {
+		if _, ok := param.Type().Underlying().(*types.Slice); !ok {
+			continue
+		}
+		if _, ok := f.underlyingType(param.Type()).(*types.Struct); ok {
+			return param
+		}
+	}
+	return nil
+}
+
+func (f *functions) hasMetaQuery(method types.Object) bool {
+	_, group, err := f.subjectMeta(method)
+	if err != nil || len(group) == 0 {
+		return false
+	}
+	query, ok := group[0].Property("Query").(string)
+	return ok && len(query) > 0
+}
+
+//structParam return the first param which is a struct or a pointer to struct
+func (f *functions) structParam(method types.Object) types.Object {
+	for _, param := range f.methodParamsWithoutCtx(method) {
+		if _, ok := unalias(param.Type()).(*types.Slice); ok {
+			continue
+		}
+		if _, ok := f.underlyingType(param.Type()).(*types.Struct); ok {
+			return param
+		}
+	}
+	return nil
+}
+
+//splitUpdateMethodName split the method name without Update prefix to the fields to set and the filter part,
+//e.g. NameAndGenderById -> [Name Gender], ById
+func (f *functions) splitUpdateMethodName(str string) (setFields []string, filterPart string) {
+	byIndex := byKeywordRegexp.FindStringIndex(str)
+	setPart := str
+	if byIndex != nil {
+		setPart = str[:byIndex[0]]
+		filterPart = str[byIndex[0]:]
+	}
+	if len(setPart) == 0 {
+		return
+	}
+
+	lastIndex := 0
+	for _, andIndex := range andKeywordRegexp.FindAllStringIndex(setPart, -1) {
+		setFields = append(setFields, setPart[lastIndex:andIndex[0]])
+		lastIndex = andIndex[0] + len(query.LogicOperatorAnd)
+	}
+	setFields = append(setFields, setPart[lastIndex:])
+	return
+}
+
+//entityUpdateSets build the set clauses whose values are come from the entity param fields,
+//all fields except the id field will be set when setFields is empty,
+//then the omitempty fields are set by omitemptySets only if they are not zero
+func (f *functions) entityUpdateSets(entityParam types.Object, setFields []string,
+	dialectEngine engine.Engine) (sets, omitemptySets []string, err error) {
+
+	entityStruct := f.underlyingType(entityParam.Type()).(*types.Struct)
+	columnFields := f.columnFields(entityStruct)
+	sets = make([]string, 0, len(columnFields))
+	setClause := func(columnField *columnField) string {
+		return fmt.Sprintf("%s = :%s.%s", dialectEngine.Escape(columnField.column),
+			entityParam.Name(), columnField.field.Name())
+	}
+
+	if len(setFields) == 0 {
+		idField := f.idFieldOf(entityStruct)
+		for _, columnField := range columnFields {
+			if columnField.field == idField {
+				continue
+			}
+			if columnField.hasOption("omitempty") {
+				omitemptySets = append(omitemptySets, fmt.Sprintf("{{if .%s.%s}} %s, {{end}}",
+					entityParam.Name(), columnField.fieldPath(), setClause(columnField)))
+				continue
+			}
+			sets = append(sets, setClause(columnField))
+		}
+		return
+	}
+
+	fieldNameToColumnField := make(map[string]*columnField, len(columnFields))
+	for _, columnField := range columnFields {
+		fieldNameToColumnField[columnField.field.Name()] = columnField
+	}
+	for _, setField := range setFields {
+		columnField, ok := fieldNameToColumnField[setField]
+		if !ok {
+			err = fmt.Errorf("can not find field %s in %s", setField, entityParam.Type().String())
+			return nil, nil, err
+		}
+		sets = append(sets, setClause(columnField))
+	}
+	return
+}
+
+//setColumnFields return the column fields of the fields to set by a partial update without a struct param,
+//the fields are looked up in the first entity of the mapper which has all of them, see mapperEntities
+func (f *functions) setColumnFields(method types.Object, setFields []string) (
+	entityStruct *types.Struct, setColumnFields []*columnField, err error) {
+
+	for _, entityStruct = range f.mapperEntities(method) {
+		fieldNameToColumnField := make(map[string]*columnField)
+		for _, columnField := range f.columnFields(entityStruct) {
+			fieldNameToColumnField[columnField.field.Name()] = columnField
+		}
+		setColumnFields = make([]*columnField, 0, len(setFields))
+		for _, setField := range setFields {
+			if columnField, ok := fieldNameToColumnField[setField]; ok {
+				setColumnFields = append(setColumnFields, columnField)
+			}
+		}
+		if len(setColumnFields) == len(setFields) {
+			return
+		}
+	}
+	return nil, nil, fmt.Errorf("can not find fields %v in the entities of the mapper", setFields)
+}
+
+//mapperEntities return the entities of the mapper of method, they are the struct params of the insert, update and
+//upsert methods, then the struct rows of the select methods
+func (f *functions) mapperEntities(method types.Object) []*types.Struct {
+	recv := method.Type().(*types.Signature).Recv()
+	if recv == nil {
+		return nil
+	}
+	mapperInterface, ok := recv.Type().Underlying().(*types.Interface)
+	if !ok {
+		return nil
+	}
+	var paramEntities, rowEntities []*types.Struct
+	for i := 0; i < mapperInterface.NumMethods(); i++ {
+		mapperMethod := mapperInterface.Method(i)
+		queryType, err := f.QueryType(mapperMethod)
+		if err != nil {
+			continue
+		}
+		switch queryType {
+		case MetaInsert, MetaUpdate, MetaUpsert:
+			param := f.structParam(mapperMethod)
+			if param == nil {
+				param = f.BatchParam(mapperMethod)
+			}
+			if param != nil {
+				paramEntities = append(paramEntities, f.underlyingType(param.Type()).(*types.Struct))
+			}
+		case MetaSelect:
+			if rowStruct := f.nestedStruct(f.ItemType(mapperMethod)); rowStruct != nil {
+				rowEntities = append(rowEntities, rowStruct)
+			}
+		}
+	}
+	return append(paramEntities, rowEntities...)
+}
+
+func (f *functions) hasKeyword(methodName string, keywords []string) bool {
+	for _, keyword := range keywords {
+		if strings.HasPrefix(methodName, keyword) {
+			return true
+		}
+	}
+	return false
+}
+
+func (f *functions) scanTargetsForSingleColumn(itemType types.Type, columns []*parser.Column,
+	item string) ([]*scanTarget, error) {
+	if len(columns) > 1 || columns[0].Alias == "*" {
+		return nil, errors.New("when the query result is a basic type, select must be a specified field")
+	}
+	return []*scanTarget{{expr: item, typ: itemType, name: "Item"}}, nil
+}
+
+func (f *functions) scanTargetsForStruct(rowType *types.Struct, columns []*parser.Column,
+	item string) ([]*scanTarget, error) {
+	if len(columns) == 1 && columns[0].Alias == "*" {
+		return f.scanTargetsForStar(rowType, item), nil
+	} else {
+		return f.scanTargetsForMultipleColumn(rowType, columns, item)
+	}
+
+}
+
+func (f *functions) scanTargetsForStar(rowType *types.Struct, item string) []*scanTarget {
+	columnFields := f.columnFields(rowType)
+	targets := make([]*scanTarget, 0, len(columnFields))
+	for _, columnField := range columnFields {
+		targets = append(targets, f.scanTargetOf(columnField, item))
+	}
+	return targets
+}
+
+func (f *functions) scanTargetsForMultipleColumn(rowType *types.Struct, columns []*parser.Column,
+	item string) (targets []*scanTarget, err error) {
+
+	targets = make([]*scanTarget, 0, len(columns))
+	columnFields := f.scanColumnFields(rowType)
+	for _, column := range columns {
+		if column.Alias == "*" {
+			err = fmt.Errorf("msql: unsupported * mixed with specified fields query")
+			return
+		}
+		columnField := f.findColumnField(columnFields, column.Alias)
+		if columnField == nil {
+			err = fmt.Errorf("msql: can't find field of column in struct, column=%s,rowType=%s",
+				column.Alias, rowType.String())
+			return
+		}
+		targets = append(targets, f.scanTargetOf(columnField, item))
+	}
+	return
+}
+
+func (f *functions) scanTargetOf(columnField *columnField, item string) *scanTarget {
+	return &scanTarget{
+		expr:     item + "." + columnField.fieldPath(),
+		name:     strings.ReplaceAll(columnField.fieldPath(), ".", ""),
+		typ:      columnField.field.Type(),
+		nullable: columnField.hasOption("nullable"),
+		json:     columnField.hasOption("json"),
+	}
+}
+
+//findColumnField find the field of the column, the column is matched by the db tag or the snake case field name,
+//then by the camel case of the column if the field is not named by the db tag
+func (f *functions) findColumnField(columnFields []*columnField, column string) *columnField {
+	for _, columnField := range columnFields {
+		if strings.EqualFold(columnField.column, column) {
+			return columnField
+		}
+	}
+	fieldName := xstrings.ToCamelCase(column)
+	for _, columnField := range columnFields {
+		if len(columnField.path) == 0 && !columnField.tagged && columnField.field.Name() == fieldName {
+			return columnField
+		}
+	}
+	return nil
+}
+
+func (f *functions) connectTableQualifier(tableQualifier, column string) string {
+	if len(tableQualifier) == 0 {
+		return column
+	}
+	return tableQualifier + "." + column
+}
+
+//columnEngine build the columns of the fields by the db tags of rowStruct
+type columnEngine struct {
+	engine.Engine
+	columns map[string]string
+}
+
+func (e *columnEngine) BuildColumn(str string) string {
+	if column, ok := e.columns[str]; ok {
+		return column
+	}
+	return e.Engine.BuildColumn(str)
+}
+
+func (f *functions) columnEngine(dialectEngine engine.Engine, rowStruct *types.Struct) engine.Engine {
+	if rowStruct == nil {
+		return dialectEngine
+	}
+	columnFields := f.columnFields(rowStruct)
+	columns := make(map[string]string, len(columnFields))
+	for _, columnField := range columnFields {
+		columns[columnField.field.Name()] = columnField.column
+	}
+	return &columnEngine{Engine: dialectEngine, columns: columns}
+}
+
+//engine return the engine of the mapper dialect, nil if the dialect is unsupported
+func (f *functions) engine(mapper *Mapper) engine.Engine {
+	name := strings.ToLower(mapper.Dialect)
+	if len(name) == 0 {
+		return f.defaultEngine
+	}
+	if alias, ok := dialectAliases[name]; ok {
+		name = alias
+	}
+	if dialectEngine, ok := f.engines[name]; ok {
+		return dialectEngine
+	}
+	return engine.Engines[name]
+}
+
+//translateQuery translate the derived query, the fields of the query are mapped to the columns of rowStruct,
+//rowStruct can be nil
+func (f *functions) translateQuery(mapper *Mapper, q *query.Query, rowStruct *types.Struct) (sql string, err error) {
+	dialectEngine := f.engine(mapper)
+	if dialectEngine == nil {
+		err = fmt.Errorf("unsupported dialect,dialect=%s", mapper.Dialect)
+		return
+	}
+	translator := query.NewRDBTranslator(f.columnEngine(dialectEngine, rowStruct))
+	return translator.Translate(context.Background(), q)
+}
+
+func (f *functions) translateFilterGroup(mapper *Mapper, fg *query.FilterGroup, rowStruct *types.Struct) (
+	sql string, err error) {
+
+	dialectEngine := f.engine(mapper)
+	if dialectEngine == nil {
+		err = fmt.Errorf("unsupported dialect,dialect=%s", mapper.Dialect)
+		return
+	}
+	translator := query.NewRDBTranslator(f.columnEngine(dialectEngine, rowStruct))
+	return translator.TranslateFilterGroup(context.Background(), fg)
+}
+
+//compileNamedQuery compile the named query to the query of the dialect bindvars and the names of the bindvars,
+//the dynamic query is compiled as rendered with all the if fragments kept
+func (f *functions) compileNamedQuery(namedQuery, dialect string) (query string, names []string, err error) {
+	bindType := sqlx.BindType(dialect)
+	if bindType == 0 {
+		err = fmt.Errorf("unsupported dialect,dialect=%s", dialect)
+		return
+	}
+	if isDynamicQuery(namedQuery) {
+		namedQuery, err = renderQuery(namedQuery)
+		if err != nil {
+			return
+		}
+	}
+	query, names, err = sqlx.CompileNamedQuery([]byte(namedQuery), bindType)
+	return
+}
+
+func (f *functions) subjectMeta(method types.Object) (metaName string, group meta.Group, err error) {
+	metaGroups := f.metaParser.ObjectMetaGroups(method, MetaNames...)
+	if len(metaGroups) > 1 {
+		err = fmt.Errorf("method can not use multiple %v,method=%s", MetaNames, method.String())
+	} else if len(metaGroups) == 1 {
+		for k, v := range metaGroups {
+			metaName = k
+			group = v
+			break
+		}
+	}
+	return
+}
+
+//methodParamsWithoutCtx return the params to bind as the query args,
+//the ctx, the callback param, the pager param, the cursor param and the sort param are excluded
+func (f *functions) methodParamsWithoutCtx(method types.Object) []types.Object {
 	methodParams := f.pkgParser.Params(method)
 	var toArgMethodParams []types.Object
 	if len(methodParams) > 0 && f.pkgParser.AssignableToCtx(methodParams[0].Type()) {
@@ -427,5 +2594,13 @@ func (f *functions) methodParamsWithoutCtx(method types.Object) []types.Object {
 	} else {
 		toArgMethodParams = methodParams
 	}
-	return toArgMethodParams
+	excludedParams := []types.Object{f.CallbackParam(method), f.PagerParam(method), f.CursorParam(method),
+		f.SortParam(method)}
+	params := make([]types.Object, 0, len(toArgMethodParams))
+	for _, param := range toArgMethodParams {
+		if !containsObject(excludedParams, param) {
+			params = append(params, param)
+		}
+	}
+	return params
 }
@@ -0,0 +1,553 @@
+package sqlmap
+
+import (
+	"go/ast"
+	goparser "go/parser"
+	"go/token"
+	"go/types"
+	"reflect"
+	"testing"
+
+	"github.com/gomelon/melon/data/engine"
+	"github.com/gomelon/meta"
+	"github.com/gomelon/sqlmap/dialect"
+	"github.com/gomelon/sqlmap/parser"
+)
+
+type customEngine struct {
+	*engine.MySQL
+}
+
+func (c *customEngine) Dialect() string {
+	return "custom"
+}
+
+func Test_functions_engine(t *testing.T) {
+	f := &functions{
+		defaultEngine: engine.NewMySQL(),
+		engines:       map[string]engine.Engine{},
+	}
+	WithEngines(engine.NewMySQL(), dialect.NewPostgres(), dialect.NewSQLite())(f)
+	WithEngines(&customEngine{})(f)
+	tests := []struct {
+		name        string
+		dialect     string
+		wantDialect string
+		wantNil     bool
+	}{
+		{name: "Default", dialect: "", wantDialect: "mysql"},
+		{name: "MySQL", dialect: "MySQL", wantDialect: "mysql"},
+		{name: "Postgres", dialect: "postgres", wantDialect: "postgres"},
+		{name: "Postgres Alias", dialect: "pgx", wantDialect: "postgres"},
+		{name: "SQLite Alias", dialect: "sqlite", wantDialect: "sqlite3"},
+		{name: "Custom", dialect: "custom", wantDialect: "custom"},
+		{name: "Unsupported", dialect: "oracle", wantNil: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := f.engine(&Mapper{Dialect: tt.dialect})
+			if tt.wantNil {
+				if got != nil {
+					t.Errorf("engine() got = %v, want nil", got)
+				}
+				return
+			}
+			if got == nil || got.Dialect() != tt.wantDialect {
+				t.Errorf("engine() got = %v, want dialect %s", got, tt.wantDialect)
+			}
+		})
+	}
+}
+
+func Test_functions_columnFields(t *testing.T) {
+	newField := func(name string) *types.Var {
+		return types.NewField(token.NoPos, nil, name, types.Typ[types.String], false)
+	}
+	userID, name, email, cache, createdAt, internal := newField("UserID"), newField("Name"), newField("Email"),
+		newField("Cache"), newField("CreatedAt"), newField("internal")
+	rowStruct := types.NewStruct(
+		[]*types.Var{userID, name, email, cache, createdAt, internal},
+		[]string{`db:"uid,pk"`, `db:",omitempty" json:"name"`, `db:"mail"`, `db:"-"`, `json:"created"`, ""},
+	)
+	f := &functions{}
+	got := f.columnFields(rowStruct)
+	want := []*columnField{
+		{field: userID, column: "uid", tagged: true, options: []string{"pk"}},
+		{field: name, column: "name", options: []string{"omitempty"}},
+		{field: email, column: "mail", tagged: true},
+		{field: createdAt, column: "created_at"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("columnFields() got = %v, want %v", got, want)
+	}
+	if idField := f.idFieldOf(rowStruct); idField != userID {
+		t.Errorf("idFieldOf() got = %v, want %v", idField, userID)
+	}
+	for _, tt := range []struct {
+		column string
+		want   *types.Var
+	}{
+		{column: "UID", want: userID},
+		{column: "created_at", want: createdAt},
+		//the field named by the db tag is not matched by its go name
+		{column: "email", want: nil},
+		{column: "cache", want: nil},
+	} {
+		columnField := f.findColumnField(got, tt.column)
+		if (columnField == nil && tt.want != nil) || (columnField != nil && columnField.field != tt.want) {
+			t.Errorf("findColumnField(%s) got = %v, want %v", tt.column, columnField, tt.want)
+		}
+	}
+}
+
+func Test_functions_scanColumnFields(t *testing.T) {
+	pkg := types.NewPackage("example.com/model", "model")
+	newField := func(name string, typ types.Type, embedded bool) *types.Var {
+		return types.NewField(token.NoPos, pkg, name, typ, embedded)
+	}
+	newNamed := func(name string, fields []*types.Var, tags []string) *types.Named {
+		return types.NewNamed(types.NewTypeName(token.NoPos, pkg, name, nil), types.NewStruct(fields, tags), nil)
+	}
+	id, createdAt := newField("Id", types.Typ[types.Int64], false), newField("CreatedAt", types.Typ[types.String], false)
+	baseModel := newNamed("BaseModel", []*types.Var{id, createdAt}, nil)
+	phone := newField("Phone", types.Typ[types.String], false)
+	address := newNamed("Address", []*types.Var{phone}, nil)
+
+	embedded := newField("BaseModel", baseModel, true)
+	name := newField("Name", types.Typ[types.String], false)
+	shadowCreatedAt := newField("CreatedAt", types.Typ[types.Int64], false)
+	addressField := newField("Address", address, false)
+	jsonAddressField := newField("LastAddress", types.NewPointer(address), false)
+	rowStruct := types.NewStruct(
+		[]*types.Var{embedded, name, shadowCreatedAt, addressField, jsonAddressField},
+		[]string{"", "", "", `db:"addr"`, `db:",json"`},
+	)
+
+	f := &functions{}
+	wantColumnFields := []*columnField{
+		{field: name, column: "name"},
+		{field: shadowCreatedAt, column: "created_at"},
+		{field: jsonAddressField, column: "last_address", options: []string{"json"}},
+		{field: id, column: "id"},
+	}
+	if got := f.columnFields(rowStruct); !reflect.DeepEqual(got, wantColumnFields) {
+		t.Errorf("columnFields() got = %v, want %v", got, wantColumnFields)
+	}
+
+	got := f.scanColumnFields(rowStruct)
+	wantScanColumnFields := []*columnField{
+		{field: name, column: "name"},
+		{field: shadowCreatedAt, column: "created_at"},
+		{field: phone, path: "Address.Phone", column: "addr__phone"},
+		{field: jsonAddressField, column: "last_address", options: []string{"json"}},
+		{field: id, column: "id"},
+	}
+	if !reflect.DeepEqual(got, wantScanColumnFields) {
+		t.Errorf("scanColumnFields() got = %v, want %v", got, wantScanColumnFields)
+	}
+	if columnField := f.findColumnField(got, "addr__phone"); columnField == nil ||
+		columnField.fieldPath() != "Address.Phone" {
+		t.Errorf("findColumnField() got = %v, want Address.Phone", columnField)
+	}
+}
+
+func Test_functions_structField(t *testing.T) {
+	pkg := types.NewPackage("example.com/model", "model")
+	newField := func(name string) *types.Var {
+		return types.NewField(token.NoPos, pkg, name, types.Typ[types.String], false)
+	}
+	newParam := func(name string, typ types.Type) types.Object {
+		return types.NewParam(token.NoPos, pkg, name, typ)
+	}
+	city, receiver := newField("City"), newField("Name")
+	address := types.NewNamed(types.NewTypeName(token.NoPos, pkg, "Address", nil),
+		types.NewStruct([]*types.Var{city, receiver}, nil), nil)
+	name, bornAfter := newField("Name"), newField("Birthday")
+	addressField := types.NewField(token.NoPos, pkg, "Address", address, false)
+	filter := types.NewNamed(types.NewTypeName(token.NoPos, pkg, "UserFilter", nil),
+		types.NewStruct([]*types.Var{name, bornAfter, addressField}, []string{"", `db:"born_after"`, `db:"addr"`}), nil)
+
+	filterParam := newParam("filter", types.NewPointer(filter))
+	addressParam := newParam("address", address)
+	params := []types.Object{newParam("names", types.NewSlice(filter)), filterParam, addressParam}
+	tests := []struct {
+		name      string
+		queryName string
+		wantParam types.Object
+		wantPath  string
+		wantErr   bool
+	}{
+		{name: "Field", queryName: "City", wantParam: addressParam, wantPath: "City"},
+		{name: "Tag", queryName: "born_after", wantParam: filterParam, wantPath: "Birthday"},
+		{name: "Qualified Nested", queryName: "filter.Address.City", wantParam: filterParam, wantPath: "Address.City"},
+		{name: "Nested Column", queryName: "addr__city", wantParam: filterParam, wantPath: "Address.City"},
+		{name: "Ambiguous", queryName: "Name", wantErr: true},
+		{name: "Qualified", queryName: "filter.Name", wantParam: filterParam, wantPath: "Name"},
+		{name: "Not Found", queryName: "filter.Gender", wantErr: true},
+	}
+	f := &functions{}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			param, columnField, err := f.structField(tt.queryName, params)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("structField() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if tt.wantErr {
+				return
+			}
+			if param != tt.wantParam || columnField.fieldPath() != tt.wantPath {
+				t.Errorf("structField() got = %v %v, want %v %v", param, columnField.fieldPath(), tt.wantParam,
+					tt.wantPath)
+			}
+		})
+	}
+}
+
+func Test_functions_nameArgsStr(t *testing.T) {
+	pkg := types.NewPackage("example.com/model", "model")
+	newParam := func(name string, typ types.Type) types.Object {
+		return types.NewParam(token.NoPos, pkg, name, typ)
+	}
+	user := types.NewNamed(types.NewTypeName(token.NoPos, pkg, "User", nil), types.NewStruct(
+		[]*types.Var{types.NewField(token.NoPos, pkg, "Name", types.Typ[types.String], false)}, nil), nil)
+	params := []types.Object{newParam("id", types.Typ[types.Int64]), newParam("user", types.NewPointer(user))}
+	tests := []struct {
+		name       string
+		queryNames []string
+		want       string
+		wantErr    bool
+	}{
+		{name: "Reordered", queryNames: []string{"name", "id"}, want: "user.Name,id,"},
+		{name: "Repeated", queryNames: []string{"id", "user.Name", "id"}, want: "id,user.Name,id,"},
+		{name: "Misspelled", queryNames: []string{"idd", "name"}, wantErr: true},
+		{name: "Unused", queryNames: []string{"id"}, wantErr: true},
+	}
+	f := &functions{}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := f.nameArgsStr(tt.queryNames, params)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("nameArgsStr() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("nameArgsStr() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_functions_scanOf(t *testing.T) {
+	pkg := types.NewPackage("example.com/model", "model")
+	gender := types.NewNamed(types.NewTypeName(token.NoPos, pkg, "Gender", nil), types.Typ[types.Uint8], nil)
+	tags := types.NewNamed(types.NewTypeName(token.NoPos, pkg, "Tags", nil), types.NewSlice(types.Typ[types.String]), nil)
+	f := &functions{importTracker: meta.NewDefaultImportTracker(pkg.Path()), converters: map[string]*converter{}}
+	err := f.registerConverter(newConverterFunc(pkg, "ParseTags", types.Typ[types.String], tags, true))
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name     string
+		targets  []*scanTarget
+		nullable bool
+		want     *Scan
+		wantErr  bool
+	}{
+		{
+			name: "Not Nullable",
+			targets: []*scanTarget{
+				{expr: "_item.Id", name: "Id", typ: types.Typ[types.Int64]},
+				{expr: "_item.Name", name: "Name", typ: types.Typ[types.String]},
+			},
+			want: &Scan{Args: "&_item.Id, &_item.Name"},
+		},
+		{
+			name: "Nullable Tag",
+			targets: []*scanTarget{
+				{expr: "_item.Id", name: "Id", typ: types.Typ[types.Int64]},
+				{expr: "_item.Gender", name: "Gender", typ: gender, nullable: true},
+				{expr: "_item.Remark", name: "Remark", typ: types.NewPointer(types.Typ[types.String]), nullable: true},
+			},
+			want: &Scan{
+				Vars:    []string{"var _nullGender sql.NullByte"},
+				Args:    "&_item.Id, &_nullGender, &_item.Remark",
+				Assigns: []*ScanAssign{{Target: "_item.Gender", Value: "Gender(_nullGender.Byte)"}},
+			},
+		},
+		{
+			name:     "Nullable Query",
+			targets:  []*scanTarget{{expr: "_item", name: "Item", typ: types.Typ[types.Int]}},
+			nullable: true,
+			want: &Scan{
+				Vars:    []string{"var _nullItem sql.NullInt64"},
+				Args:    "&_nullItem",
+				Assigns: []*ScanAssign{{Target: "_item", Value: "int(_nullItem.Int64)"}},
+			},
+		},
+		{
+			name: "Converter",
+			targets: []*scanTarget{
+				{expr: "_item.Id", name: "Id", typ: types.Typ[types.Int64]},
+				{expr: "_item.Tags", name: "Tags", typ: tags},
+			},
+			want: &Scan{
+				Vars:    []string{"var _convTags string"},
+				Args:    "&_item.Id, &_convTags",
+				Assigns: []*ScanAssign{{Target: "_item.Tags", Value: "ParseTags(_convTags)", Err: true}},
+			},
+		},
+		{
+			name: "JSON",
+			targets: []*scanTarget{
+				{expr: "_item.Id", name: "Id", typ: types.Typ[types.Int64]},
+				{expr: "_item.Tags", name: "Tags", typ: tags, nullable: true, json: true},
+			},
+			want: &Scan{
+				Vars:    []string{"var _jsonTags []byte"},
+				Args:    "&_item.Id, &_jsonTags",
+				Assigns: []*ScanAssign{{Value: "dao.UnmarshalJSON(_jsonTags, &_item.Tags)", Err: true}},
+			},
+		},
+		{
+			name:     "Nullable Converter",
+			targets:  []*scanTarget{{expr: "_item", name: "Item", typ: tags}},
+			nullable: true,
+			want: &Scan{
+				Vars:    []string{"var _nullItem sql.NullString"},
+				Args:    "&_nullItem",
+				Assigns: []*ScanAssign{{Target: "_item", Value: "ParseTags(_nullItem.String)", Err: true}},
+			},
+		},
+		{
+			name:     "Unsupported",
+			targets:  []*scanTarget{{expr: "_item", name: "Item", typ: types.NewMap(types.Typ[types.String], types.Typ[types.Int])}},
+			nullable: true,
+			wantErr:  true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := f.scanOf(tt.targets, tt.nullable)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("scanOf() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("scanOf() got = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+//newConverterFunc return a func(param) result or func(param) (result, error)
+func newConverterFunc(pkg *types.Package, name string, param, result types.Type, withErr bool) *types.Func {
+	results := []*types.Var{types.NewVar(token.NoPos, pkg, "", result)}
+	if withErr {
+		results = append(results, types.NewVar(token.NoPos, pkg, "", types.Universe.Lookup("error").Type()))
+	}
+	signature := types.NewSignatureType(nil, nil, nil,
+		types.NewTuple(types.NewVar(token.NoPos, pkg, "", param)), types.NewTuple(results...), false)
+	return types.NewFunc(token.NoPos, pkg, name, signature)
+}
+
+func Test_functions_registerConverter(t *testing.T) {
+	pkg := types.NewPackage("example.com/model", "model")
+	status := types.NewNamed(types.NewTypeName(token.NoPos, pkg, "Status", nil), types.Typ[types.Uint8], nil)
+	decimalPkg := types.NewPackage("github.com/shopspring/decimal", "decimal")
+	decimal := types.NewNamed(types.NewTypeName(token.NoPos, decimalPkg, "Decimal", nil), types.NewStruct(nil, nil), nil)
+	tests := []struct {
+		name      string
+		fn        *types.Func
+		wantScan  types.Type
+		wantValue types.Type
+		wantArg   string
+		wantErr   bool
+	}{
+		{
+			name:     "Scan",
+			fn:       newConverterFunc(pkg, "ParseStatus", types.Typ[types.String], status, false),
+			wantScan: status,
+		},
+		{
+			name:      "Value",
+			fn:        newConverterFunc(pkg, "FormatStatus", status, types.Typ[types.String], false),
+			wantValue: status,
+			wantArg:   "FormatStatus(_item.Status)",
+		},
+		{
+			name:      "Value With Error",
+			fn:        newConverterFunc(decimalPkg, "Format", decimal, types.Typ[types.String], true),
+			wantValue: decimal,
+			wantArg:   "dao.ValueFunc(_item.Status, decimal.Format)",
+		},
+		{
+			name:    "Both Driver Types",
+			fn:      newConverterFunc(pkg, "Itoa", types.Typ[types.Int], types.Typ[types.String], false),
+			wantErr: true,
+		},
+		{
+			name:    "Neither Driver Type",
+			fn:      newConverterFunc(pkg, "ToDecimal", status, decimal, false),
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := &functions{importTracker: meta.NewDefaultImportTracker(pkg.Path()), converters: map[string]*converter{}}
+			err := f.registerConverter(tt.fn)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("registerConverter() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if tt.wantScan != nil && f.converter(tt.wantScan).scan != tt.fn {
+				t.Errorf("registerConverter() scan converter of %s not registered", tt.wantScan)
+			}
+			if tt.wantValue != nil {
+				if f.converter(tt.wantValue).value != tt.fn {
+					t.Errorf("registerConverter() value converter of %s not registered", tt.wantValue)
+				}
+				if got := f.bindArg("_item.Status", tt.wantValue); got != tt.wantArg {
+					t.Errorf("bindArg() got = %v, want %v", got, tt.wantArg)
+				}
+			}
+		})
+	}
+}
+
+func Test_functions_RowMap(t *testing.T) {
+	//any is an alias since go1.23 with gotypesalias=1, the default of the modules of go1.23 and later
+	t.Setenv("GODEBUG", "gotypesalias=1")
+	fset := token.NewFileSet()
+	file, err := goparser.ParseFile(fset, "row.go", `package model
+		type Row = map[string]any
+		var row map[string]any
+		var rows []map[string]any`, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pkg, err := (&types.Config{}).Check("example.com/model", fset, []*ast.File{file}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	declaredType := func(name string) types.Type {
+		return pkg.Scope().Lookup(name).Type()
+	}
+	anyType := types.NewInterfaceType(nil, nil)
+	tests := []struct {
+		name string
+		typ  types.Type
+		want bool
+	}{
+		{name: "Row Map", typ: types.NewMap(types.Typ[types.String], anyType), want: true},
+		{name: "Int Key", typ: types.NewMap(types.Typ[types.Int64], anyType), want: false},
+		{name: "String Value", typ: types.NewMap(types.Typ[types.String], types.Typ[types.String]), want: false},
+		{name: "Slice", typ: types.NewSlice(anyType), want: false},
+		{name: "Declared Any", typ: declaredType("row"), want: true},
+		{name: "Declared Alias", typ: declaredType("Row"), want: true},
+		{name: "Declared Slice", typ: declaredType("rows"), want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := &functions{}
+			if got := f.RowMap(tt.typ); got != tt.want {
+				t.Errorf("RowMap() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_functions_RowsItem(t *testing.T) {
+	daoPkg := types.NewPackage("github.com/gomelon/sqlmap/dao", "dao")
+	newGeneric := func(pkg *types.Package, name string) *types.Named {
+		typeParam := types.NewTypeParam(types.NewTypeName(token.NoPos, pkg, "T", nil), types.NewInterfaceType(nil, nil))
+		named := types.NewNamed(types.NewTypeName(token.NoPos, pkg, name, nil), types.NewStruct(nil, nil), nil)
+		named.SetTypeParams([]*types.TypeParam{typeParam})
+		return named
+	}
+	instantiate := func(generic *types.Named, typeArg types.Type) types.Type {
+		instance, err := types.Instantiate(nil, generic, []types.Type{typeArg}, false)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return instance
+	}
+	rows := newGeneric(daoPkg, "Rows")
+	otherRows := newGeneric(types.NewPackage("example.com/model", "model"), "Rows")
+	tests := []struct {
+		name string
+		typ  types.Type
+		want types.Type
+	}{
+		{name: "Rows", typ: types.NewPointer(instantiate(rows, types.Typ[types.String])), want: types.Typ[types.String]},
+		{name: "Not Pointer", typ: instantiate(rows, types.Typ[types.String]), want: nil},
+		{name: "Other Package", typ: types.NewPointer(instantiate(otherRows, types.Typ[types.String])), want: nil},
+		{name: "Slice", typ: types.NewSlice(types.Typ[types.String]), want: nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := &functions{}
+			if got := f.RowsItem(tt.typ); got != tt.want {
+				t.Errorf("RowsItem() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_functions_bindVar(t *testing.T) {
+	tests := []struct {
+		name    string
+		dialect string
+		n       int
+		want    string
+		wantErr bool
+	}{
+		{name: "MySQL", dialect: "mysql", n: 3, want: "?"},
+		{name: "SQLite", dialect: "sqlite3", n: 3, want: "?"},
+		{name: "Postgres", dialect: "postgres", n: 3, want: "$3"},
+		{name: "Unsupported", dialect: "oracle", n: 3, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := &functions{}
+			got, err := f.bindVar(tt.dialect, tt.n)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("bindVar() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("bindVar() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_functions_cursorKeyset(t *testing.T) {
+	tests := []struct {
+		name    string
+		cursor  string
+		want    *parser.Keyset
+		wantErr bool
+	}{
+		{name: "Single", cursor: "id", want: &parser.Keyset{Columns: []string{"id"}}},
+		{name: "Asc", cursor: "name ASC, id asc", want: &parser.Keyset{Columns: []string{"name", "id"}}},
+		{name: "Desc", cursor: "birthday DESC,id DESC",
+			want: &parser.Keyset{Columns: []string{"birthday", "id"}, Desc: true}},
+		{name: "MixedDirections", cursor: "birthday DESC,id ASC", wantErr: true},
+		{name: "MixedDefaultDirection", cursor: "birthday DESC,id", wantErr: true},
+		{name: "InvalidDirection", cursor: "id DOWN", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := &functions{}
+			got, err := f.cursorKeyset(tt.cursor)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("cursorKeyset() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("cursorKeyset() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
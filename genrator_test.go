@@ -12,19 +12,28 @@ import (
 func TestTemplateGen(t *testing.T) {
 
 	workdir, _ := os.Getwd()
-	path := workdir + "/testdata"
-	generator, err := meta.NewTmplPkgGen(path, TmplSQL, meta.WithOutputFilename("sql_dao"),
-		meta.WithFuncMapFactory(func(generator *meta.TmplPkgGen) template.FuncMap {
-			return NewFunctions(generator, engine.NewMySQL()).FuncMap()
-		}))
-	if err != nil {
-		fmt.Println(err.Error())
-		return
-	}
-	//err = generator.Print()
-	err = generator.Generate()
-	//err = generator.Generate()
-	if err != nil {
-		fmt.Println(err.Error())
+	for _, pkg := range []struct {
+		path string
+		opts []Option
+	}{
+		{path: workdir + "/testdata"},
+		{path: workdir + "/testdata/sqlite", opts: []Option{WithSchema("schema")}},
+		{path: workdir + "/testdata/multiple"},
+	} {
+		opts := pkg.opts
+		generator, err := meta.NewTmplPkgGen(pkg.path, TmplSQL, meta.WithOutputFilename("sql_dao"),
+			meta.WithFuncMapFactory(func(generator *meta.TmplPkgGen) template.FuncMap {
+				return NewFunctions(generator, engine.NewMySQL(), opts...).FuncMap()
+			}))
+		if err != nil {
+			fmt.Println(err.Error())
+			return
+		}
+		//err = generator.Print()
+		err = generator.Generate()
+		//err = generator.Generate()
+		if err != nil {
+			fmt.Println(err.Error())
+		}
 	}
 }
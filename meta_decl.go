@@ -10,12 +10,12 @@ import (
 //go:embed ctx_sql_db.tmpl
 var TmplSQL string
 
-func DefaultPkgGenFactory(defaultEngine engine.Engine) meta.PkgGenFactory {
+func DefaultPkgGenFactory(defaultEngine engine.Engine, opts ...Option) meta.PkgGenFactory {
 	return meta.NewTmplPkgGenFactory(TmplSQL,
 		meta.WithOutputFilename("sql_dao"),
 		meta.WithFuncMapFactory(
 			func(generator *meta.TmplPkgGen) template.FuncMap {
-				return NewFunctions(generator, defaultEngine).FuncMap()
+				return NewFunctions(generator, defaultEngine, opts...).FuncMap()
 			},
 		),
 	)
@@ -27,11 +27,15 @@ const (
 	MetaInsert = "sqlmap.Insert"
 	MetaUpdate = "sqlmap.Update"
 	MetaDelete = "sqlmap.Delete"
+	MetaUpsert = "sqlmap.Upsert"
 	MetaNone   = "sqlmap.None"
+
+	MetaConverter = "sqlmap.Converter"
 )
 
 var (
-	MetaNames = []string{MetaMapper, MetaSelect, MetaInsert, MetaUpdate, MetaDelete, MetaNone}
+	//MetaNames the metas of the mapper methods, MetaConverter is declared on the funcs, so it is not one of them
+	MetaNames = []string{MetaMapper, MetaSelect, MetaInsert, MetaUpdate, MetaDelete, MetaUpsert, MetaNone}
 )
 
 //Mapper
@@ -48,8 +52,17 @@ type Querier interface {
 //Select
 //+meta.Decl
 type Select struct {
-	Query  string
-	Master bool
+	Query    string
+	Master   bool
+	Nullable bool //scan all the columns through sql.Null* temporaries, NULL is coerced to the zero value
+	//MapKey the column as the key of the map result, e.g. id of map[int64]*User,
+	//the query is derived from the method name if Query is empty
+	MapKey string
+	//Cursor the selected columns of the keyset separated by comma for the method returning *dao.CursorPage,
+	//e.g. id or created_at,id, append DESC to every column to order the rows descending, e.g. created_at DESC,id DESC.
+	//The keyset must be unique, e.g. end with the primary key, otherwise the rows of the same keyset are skipped
+	//across pages. The columns must be ordered in the same direction, created_at DESC,id ASC is rejected
+	Cursor string
 }
 
 func (s *Select) GetQuery() string {
@@ -60,6 +73,16 @@ func (s *Select) GetQuery() string {
 //+meta.Decl
 type Insert struct {
 	Query string
+	//MaxPlaceholders the max placeholders of a batch insert statement, default by dialect
+	MaxPlaceholders int
+	//LastInsertId the int64 result of the method is the generated id instead of the rows affected
+	LastInsertId bool
+	//ConsecutiveIds the ids generated by a batch insert statement are consecutive in mysql and sqlite,
+	//then the ids of the rows are filled by LastInsertId, otherwise they are not filled.
+	//They are not consecutive in mysql with innodb_autoinc_lock_mode=2, the default of mysql 8,
+	//or auto_increment_increment>1. In postgres the ids are filled by RETURNING in the order of the rows,
+	//which assumes that the rows are returned in the order of VALUES, postgres does not guarantee it
+	ConsecutiveIds bool
 }
 
 func (i *Insert) GetQuery() string {
@@ -86,7 +109,35 @@ func (d *Delete) GetQuery() string {
 	return d.Query
 }
 
+//Upsert insert a row or update it when the row is conflict with an existing row,
+//it is ON DUPLICATE KEY UPDATE in mysql and ON CONFLICT DO UPDATE in postgres and sqlite
+//+meta.Decl
+type Upsert struct {
+	Query string
+	//ConflictKeys the columns of the primary or unique key separated by comma, required by postgres and sqlite.
+	//If the id is a conflict key, a zero id is not inserted in postgres and sqlite, then the entity is inserted
+	//with a generated id, so the new entities never conflict with each other
+	ConflictKeys string
+	//UpdateColumns the columns to update when conflict separated by comma,
+	//default is all inserted columns except the conflict keys
+	UpdateColumns string
+	//LastInsertId the int64 result of the method is the id of the inserted or updated row instead of the rows affected
+	LastInsertId bool
+}
+
+func (u *Upsert) GetQuery() string {
+	return u.Query
+}
+
 //None
 //+meta.Decl
 type None struct {
 }
+
+//Converter a func to convert a field type which is not supported by the driver,
+//one side of the func must be a type supported by the driver, e.g. string, int64, []byte, time.Time or sql.NullString.
+//func(DBType) FieldType or func(DBType) (FieldType, error) converts the scanned column to the field,
+//func(FieldType) DBType or func(FieldType) (DBType, error) converts the field to the query arg
+//+meta.Decl
+type Converter struct {
+}
@@ -0,0 +1,152 @@
+package parser
+
+import (
+	"errors"
+	"strings"
+)
+
+//clauseKeywords the keywords starting the top level clauses of a select after the FROM clause
+var clauseKeywords = []string{"where", "group", "having", "window", "order", "limit", "offset", "fetch",
+	"for", "lock", "union", "intersect", "except"}
+
+//SplitOrderBy split the select around its top level ORDER BY clause to replace it at runtime,
+//head is the sql before the clause and tail is the sql after it, e.g. LIMIT ? or FOR UPDATE,
+//if there is no ORDER BY clause, orderBy is empty and head is the sql before the place to append it
+func SplitOrderBy(sql string) (head, orderBy, tail string, err error) {
+	positions, err := clausePositions(sql)
+	if err != nil {
+		return
+	}
+	tailPos := len(sql)
+	for _, keyword := range []string{"limit", "offset", "fetch", "for", "lock"} {
+		if pos, ok := positions[keyword]; ok && pos < tailPos {
+			tailPos = pos
+		}
+	}
+	headEnd := tailPos
+	if pos, ok := positions["order"]; ok && pos < tailPos {
+		headEnd = pos
+		orderBy = strings.TrimSpace(sql[pos:tailPos])
+	}
+	head = strings.TrimSpace(sql[:headEnd])
+	tail = strings.TrimSpace(sql[tailPos:])
+	return
+}
+
+//clausePositions return the byte positions of the top level clause keywords after the FROM clause,
+//the keywords in the quotes, comments and parentheses are skipped
+func clausePositions(sql string) (map[string]int, error) {
+	positions := make(map[string]int)
+	depth := 0
+	from := false
+	for i := 0; i < len(sql); i++ {
+		c := sql[i]
+		switch {
+		case c == '\'' || c == '"' || c == '`':
+			end := closeQuoteByte(sql, i)
+			if end < 0 {
+				return nil, errors.New("sql parser: unterminated quoted string")
+			}
+			i = end
+		case strings.HasPrefix(sql[i:], "--"):
+			end := strings.IndexByte(sql[i:], '\n')
+			if end < 0 {
+				return positions, nil
+			}
+			i += end
+		case strings.HasPrefix(sql[i:], "/*"):
+			end := strings.Index(sql[i+2:], "*/")
+			if end < 0 {
+				return nil, errors.New("sql parser: unterminated comment")
+			}
+			i += 2 + end + 1
+		case c == '(':
+			depth++
+		case c == ')':
+			depth--
+		case isWordByte(c) && (i == 0 || !isWordByte(sql[i-1])):
+			end := i
+			for end < len(sql) && isWordByte(sql[end]) {
+				end++
+			}
+			word := strings.ToLower(sql[i:end])
+			if depth == 0 {
+				if word == "from" {
+					from = true
+				} else if _, ok := positions[word]; !ok && from && containsWord(clauseKeywords, word) {
+					positions[word] = i
+				}
+			}
+			i = end - 1
+		}
+	}
+	return positions, nil
+}
+
+//parenthesized return true if the whole condition is in a pair of parentheses, e.g. (a = 1 OR b = 2)
+func parenthesized(condition string) bool {
+	if !strings.HasPrefix(condition, "(") {
+		return false
+	}
+	depth := 0
+	for i := 0; i < len(condition); i++ {
+		switch c := condition[i]; {
+		case c == '\'' || c == '"' || c == '`':
+			if end := closeQuoteByte(condition, i); end > 0 {
+				i = end
+			}
+		case c == '(':
+			depth++
+		case c == ')':
+			depth--
+			if depth == 0 {
+				return i == len(condition)-1
+			}
+		}
+	}
+	return false
+}
+
+//hasBindVar return true if the sql has the bindvar prefix outside the quotes, e.g. ? or $ of $1
+func hasBindVar(sql string, prefix string) bool {
+	for i := 0; i < len(sql); i++ {
+		switch c := sql[i]; {
+		case c == '\'' || c == '"' || c == '`':
+			if end := closeQuoteByte(sql, i); end > 0 {
+				i = end
+			}
+		case strings.HasPrefix(sql[i:], prefix):
+			return true
+		}
+	}
+	return false
+}
+
+//closeQuoteByte return the index of the quote closing the one at start, doubled quotes are escaped quotes
+func closeQuoteByte(sql string, start int) int {
+	quote := sql[start]
+	for i := start + 1; i < len(sql); i++ {
+		if sql[i] != quote {
+			continue
+		}
+		if i+1 < len(sql) && sql[i+1] == quote {
+			i++
+			continue
+		}
+		return i
+	}
+	return -1
+}
+
+func isWordByte(c byte) bool {
+	return c == '_' || c >= 0x80 || ('a' <= c && c <= 'z') || ('A' <= c && c <= 'Z') || ('0' <= c && c <= '9')
+}
+
+func containsWord(words []string, word string) bool {
+	for _, w := range words {
+		if w == word {
+			return true
+		}
+	}
+	return false
+}
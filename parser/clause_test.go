@@ -0,0 +1,63 @@
+package parser
+
+import "testing"
+
+func TestSplitOrderBy(t *testing.T) {
+	tests := []struct {
+		name        string
+		sql         string
+		wantHead    string
+		wantOrderBy string
+		wantTail    string
+		wantErr     bool
+	}{
+		{
+			name:     "No Order By",
+			sql:      "SELECT id FROM user WHERE gender = ?",
+			wantHead: "SELECT id FROM user WHERE gender = ?",
+		},
+		{
+			name:        "Order By",
+			sql:         "SELECT id FROM user WHERE gender = ? ORDER BY id DESC",
+			wantHead:    "SELECT id FROM user WHERE gender = ?",
+			wantOrderBy: "ORDER BY id DESC",
+		},
+		{
+			name:        "Order By Limit",
+			sql:         "SELECT id FROM user ORDER BY id LIMIT ?, ?",
+			wantHead:    "SELECT id FROM user",
+			wantOrderBy: "ORDER BY id",
+			wantTail:    "LIMIT ?, ?",
+		},
+		{
+			name:     "Limit",
+			sql:      "SELECT id FROM user LIMIT $1 OFFSET $2",
+			wantHead: "SELECT id FROM user",
+			wantTail: "LIMIT $1 OFFSET $2",
+		},
+		{
+			name:     "Subquery And Quoted",
+			sql:      `SELECT id, 'order by' AS "order" FROM (SELECT id FROM user ORDER BY id) u FOR UPDATE`,
+			wantHead: `SELECT id, 'order by' AS "order" FROM (SELECT id FROM user ORDER BY id) u`,
+			wantTail: "FOR UPDATE",
+		},
+		{
+			name:    "Unterminated",
+			sql:     "SELECT id FROM user WHERE name = 'a",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			head, orderBy, tail, err := SplitOrderBy(tt.sql)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("SplitOrderBy() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if head != tt.wantHead || orderBy != tt.wantOrderBy || tail != tt.wantTail {
+				t.Errorf("SplitOrderBy() got = [%v] [%v] [%v], want [%v] [%v] [%v]",
+					head, orderBy, tail, tt.wantHead, tt.wantOrderBy, tt.wantTail)
+			}
+		})
+	}
+}
@@ -0,0 +1,156 @@
+package parser
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/xwb1989/sqlparser"
+)
+
+//Keyset the keyset to query the rows after the last row of the previous page, e.g.
+//    SELECT id, name FROM user WHERE gender = ? AND id > ? ORDER BY id LIMIT ?
+type Keyset struct {
+	Columns []string //the selected columns of the keyset, e.g. created_at, id
+	Desc    bool     //the rows are ordered by the keyset descending
+	//After the bindvars of the keyset of the last row, e.g. ?, ?,
+	//the keyset condition is omitted if it is empty, it is the first page
+	After []string
+	Limit string //the bindvar of the limit
+}
+
+//keysetStmt return the select to rewrite by the keyset
+func keysetStmt(stmt sqlparser.Statement) (*sqlparser.Select, error) {
+	sel, ok := stmt.(*sqlparser.Select)
+	if !ok {
+		return nil, errors.New("sql parser: keyset is only supported by a simple select")
+	}
+	if sel.Limit != nil {
+		return nil, errors.New("sql parser: keyset select must not have a LIMIT clause")
+	}
+	return sel, nil
+}
+
+//selectedColName return the column name of the selected column to reference it in the WHERE or ORDER BY clause,
+//e.g. u.id of the selected u.id and a.city of the selected a.city AS addr__city
+func selectedColName(stmt sqlparser.Statement, column string) (*sqlparser.ColName, error) {
+	sel, ok := stmt.(*sqlparser.Select)
+	if !ok {
+		return nil, errors.New("sql parser: not a select query")
+	}
+	var star *sqlparser.StarExpr
+	for _, selectExpr := range sel.SelectExprs {
+		switch expr := selectExpr.(type) {
+		case *sqlparser.StarExpr:
+			if star == nil {
+				star = expr
+			}
+		case *sqlparser.AliasedExpr:
+			colName, isColName := expr.Expr.(*sqlparser.ColName)
+			if expr.As.EqualString(column) || (expr.As.IsEmpty() && isColName && colName.Name.EqualString(column)) {
+				if !isColName {
+					return nil, fmt.Errorf("sql parser: selected %s is not a column", column)
+				}
+				return colName, nil
+			}
+		}
+	}
+	if star == nil {
+		return nil, fmt.Errorf("sql parser: column %s is not selected", column)
+	}
+	return &sqlparser.ColName{Name: sqlparser.NewColIdent(column), Qualifier: star.TableName}, nil
+}
+
+//rewriteKeyset add the keyset condition to the WHERE clause of the select,
+//replace the ORDER BY clause by the keyset and append the LIMIT clause,
+//the locking clause, e.g. FOR UPDATE, is kept at the end
+func rewriteKeyset(sql string, exprs []string, keyset *Keyset) (string, error) {
+	if len(keyset.After) > 0 && len(keyset.After) != len(exprs) {
+		return "", fmt.Errorf("sql parser: keyset has %d columns but %d values", len(exprs), len(keyset.After))
+	}
+	positions, err := clausePositions(sql)
+	if err != nil {
+		return "", err
+	}
+	for _, keyword := range []string{"limit", "offset", "fetch", "union", "intersect", "except"} {
+		if _, ok := positions[keyword]; ok {
+			return "", fmt.Errorf("sql parser: keyset select must not have a %s clause", strings.ToUpper(keyword))
+		}
+	}
+	firstOf := func(keywords ...string) int {
+		pos := len(sql)
+		for _, keyword := range keywords {
+			if p, ok := positions[keyword]; ok && p < pos {
+				pos = p
+			}
+		}
+		return pos
+	}
+
+	lockPos := firstOf("for", "lock")
+	orderPos := firstOf("order", "for", "lock")
+	whereEnd := firstOf("group", "having", "window", "order", "for", "lock")
+	if hasBindVar(sql[orderPos:lockPos], "?") || hasBindVar(sql[orderPos:lockPos], "$") {
+		return "", errors.New("sql parser: keyset select must not have bindvars in the ORDER BY clause")
+	}
+	//the positional bindvars after the WHERE clause would be bound to the keyset values
+	if len(keyset.After) > 0 && strings.HasPrefix(keyset.After[0], "?") && hasBindVar(sql[whereEnd:], "?") {
+		return "", errors.New("sql parser: keyset select must not have bindvars after the WHERE clause")
+	}
+	var builder strings.Builder
+	builder.Grow(len(sql) + 64)
+	if len(keyset.After) == 0 {
+		builder.WriteString(strings.TrimSpace(sql[:orderPos]))
+	} else {
+		condition := keysetCondition(exprs, keyset)
+		wherePos, hasWhere := positions["where"]
+		if hasWhere {
+			where := strings.TrimSpace(sql[wherePos+len("where") : whereEnd])
+			if !parenthesized(where) {
+				where = "(" + where + ")"
+			}
+			builder.WriteString(sql[:wherePos])
+			builder.WriteString("WHERE ")
+			builder.WriteString(where)
+			builder.WriteString(" AND ")
+		} else {
+			builder.WriteString(strings.TrimSpace(sql[:whereEnd]))
+			builder.WriteString(" WHERE ")
+		}
+		builder.WriteString(condition)
+		if rest := strings.TrimSpace(sql[whereEnd:orderPos]); len(rest) > 0 {
+			builder.WriteString(" ")
+			builder.WriteString(rest)
+		}
+	}
+
+	builder.WriteString(" ORDER BY ")
+	for i, expr := range exprs {
+		if i > 0 {
+			builder.WriteString(", ")
+		}
+		builder.WriteString(expr)
+		if keyset.Desc {
+			builder.WriteString(" DESC")
+		}
+	}
+	builder.WriteString(" LIMIT ")
+	builder.WriteString(keyset.Limit)
+	if lock := strings.TrimSpace(sql[lockPos:]); len(lock) > 0 {
+		builder.WriteString(" ")
+		builder.WriteString(lock)
+	}
+	return builder.String(), nil
+}
+
+//keysetCondition return the row value comparison of the keyset, e.g. (created_at, id) > (?, ?)
+func keysetCondition(exprs []string, keyset *Keyset) string {
+	operator := " > "
+	if keyset.Desc {
+		operator = " < "
+	}
+	if len(exprs) == 1 {
+		return exprs[0] + operator + keyset.After[0]
+	}
+	return "(" + strings.Join(exprs, ", ") + ")" + operator + "(" + strings.Join(keyset.After, ", ") + ")"
+}
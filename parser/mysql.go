@@ -53,6 +53,38 @@ func (m *mySQL) SelectColumns() ([]*Column, error) {
 	return columns, nil
 }
 
+//ReturningColumns mysql has no RETURNING clause, so it is always empty
+func (m *mySQL) ReturningColumns() ([]*Column, error) {
+	return []*Column{}, nil
+}
+
+func (m *mySQL) ColumnExpr(column string) (string, error) {
+	colName, err := selectedColName(m.stmt, column)
+	if err != nil {
+		return "", err
+	}
+	return sqlparser.String(colName), nil
+}
+
+func (m *mySQL) Keyset(keyset *Keyset) (string, error) {
+	if _, err := keysetStmt(m.stmt); err != nil {
+		return "", err
+	}
+	exprs := make([]string, 0, len(keyset.Columns))
+	for _, column := range keyset.Columns {
+		expr, err := m.ColumnExpr(column)
+		if err != nil {
+			return "", err
+		}
+		exprs = append(exprs, expr)
+	}
+	return rewriteKeyset(m.SQL, exprs, keyset)
+}
+
+func (m *mySQL) References() (*References, error) {
+	return references(m.stmt)
+}
+
 func (m *mySQL) selectColumn(selectExpr sqlparser.SelectExpr) (*Column, error) {
 	column := &Column{}
 	switch expr := selectExpr.(type) {
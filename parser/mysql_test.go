@@ -160,3 +160,110 @@ func Test_mySQLParser_SelectColumns(t *testing.T) {
 		})
 	}
 }
+
+func Test_mySQLParser_Keyset(t *testing.T) {
+	type fields struct {
+		SQL string
+	}
+	tests := []struct {
+		name    string
+		fields  fields
+		keyset  *Keyset
+		want    string
+		wantErr bool
+	}{
+		{
+			name:   "First Page",
+			fields: fields{SQL: "SELECT id, name FROM user WHERE gender = ? ORDER BY name"},
+			keyset: &Keyset{Columns: []string{"id"}, Limit: "?"},
+			want:   "SELECT id, name FROM user WHERE gender = ? ORDER BY id LIMIT ?",
+		},
+		{
+			name:   "Where",
+			fields: fields{SQL: "SELECT id, name FROM user WHERE gender = ? OR age > ?"},
+			keyset: &Keyset{Columns: []string{"id"}, After: []string{"?"}, Limit: "?"},
+			want:   "SELECT id, name FROM user WHERE (gender = ? OR age > ?) AND id > ? ORDER BY id LIMIT ?",
+		},
+		{
+			name:   "No Where",
+			fields: fields{SQL: "SELECT u.id, u.created_at FROM user u GROUP BY u.id, u.created_at FOR UPDATE"},
+			keyset: &Keyset{Columns: []string{"created_at", "id"}, Desc: true, After: []string{"?", "?"}, Limit: "?"},
+			want: "SELECT u.id, u.created_at FROM user u WHERE (u.created_at, u.id) < (?, ?) " +
+				"GROUP BY u.id, u.created_at ORDER BY u.created_at DESC, u.id DESC LIMIT ? FOR UPDATE",
+		},
+		{
+			name: "Alias And Subquery",
+			fields: fields{SQL: "SELECT a.city AS addr__city FROM address a " +
+				"WHERE a.user_id IN (SELECT id FROM user WHERE gender = ? ORDER BY id)"},
+			keyset: &Keyset{Columns: []string{"addr__city"}, After: []string{"?"}, Limit: "?"},
+			want: "SELECT a.city AS addr__city FROM address a " +
+				"WHERE (a.user_id IN (SELECT id FROM user WHERE gender = ? ORDER BY id)) AND a.city > ? " +
+				"ORDER BY a.city LIMIT ?",
+		},
+		{
+			name:   "Star",
+			fields: fields{SQL: "SELECT u.* FROM user u WHERE u.name = 'where'"},
+			keyset: &Keyset{Columns: []string{"id"}, After: []string{"?"}, Limit: "?"},
+			want:   "SELECT u.* FROM user u WHERE (u.name = 'where') AND u.id > ? ORDER BY u.id LIMIT ?",
+		},
+		{
+			name:   "Parenthesized Where",
+			fields: fields{SQL: "SELECT id FROM user WHERE (gender = ?) ORDER BY id"},
+			keyset: &Keyset{Columns: []string{"id"}, After: []string{"?"}, Limit: "?"},
+			want:   "SELECT id FROM user WHERE (gender = ?) AND id > ? ORDER BY id LIMIT ?",
+		},
+		{
+			name:   "Partly Parenthesized Where",
+			fields: fields{SQL: "SELECT id FROM user WHERE (gender = ?) OR (age > ?)"},
+			keyset: &Keyset{Columns: []string{"id"}, After: []string{"?"}, Limit: "?"},
+			want:   "SELECT id FROM user WHERE ((gender = ?) OR (age > ?)) AND id > ? ORDER BY id LIMIT ?",
+		},
+		{
+			name:    "Not Selected",
+			fields:  fields{SQL: "SELECT name FROM user"},
+			keyset:  &Keyset{Columns: []string{"id"}, Limit: "?"},
+			wantErr: true,
+		},
+		{
+			name:    "Not Column",
+			fields:  fields{SQL: "SELECT COUNT(*) AS id FROM user"},
+			keyset:  &Keyset{Columns: []string{"id"}, Limit: "?"},
+			wantErr: true,
+		},
+		{
+			name:    "Limit",
+			fields:  fields{SQL: "SELECT id FROM user LIMIT 10"},
+			keyset:  &Keyset{Columns: []string{"id"}, Limit: "?"},
+			wantErr: true,
+		},
+		{
+			name:    "Bindvar After Where",
+			fields:  fields{SQL: "SELECT gender FROM user GROUP BY gender HAVING COUNT(*) > ?"},
+			keyset:  &Keyset{Columns: []string{"gender"}, After: []string{"?"}, Limit: "?"},
+			wantErr: true,
+		},
+		{
+			name:    "Union",
+			fields:  fields{SQL: "SELECT id FROM user UNION SELECT id FROM admin"},
+			keyset:  &Keyset{Columns: []string{"id"}, Limit: "?"},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m, err := NewMySQL(tt.fields.SQL)
+			if err != nil {
+				t.Errorf("NewMySQL() error = %v", err)
+				return
+			}
+			got, err := m.Keyset(tt.keyset)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Keyset() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("Keyset() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -17,6 +17,14 @@ const (
 type Parser interface {
 	Type() (Type, error)
 	SelectColumns() ([]*Column, error)
+	ReturningColumns() ([]*Column, error)
+	//ColumnExpr return the expression to reference the selected column in the WHERE or ORDER BY clause,
+	//e.g. u.id of the selected u.id and a.city of the selected a.city AS addr__city
+	ColumnExpr(column string) (string, error)
+	//Keyset rewrite the select to query the rows after the keyset of the last row of the previous page
+	Keyset(keyset *Keyset) (string, error)
+	//References return the tables and the columns referenced by the statement to validate them against a Schema
+	References() (*References, error)
 }
 
 func New(dialect string, sql string) (p Parser, err error) {
@@ -24,6 +32,10 @@ func New(dialect string, sql string) (p Parser, err error) {
 	switch dialectLower {
 	case "mysql":
 		p, err = NewMySQL(sql)
+	case "postgres", "pgx":
+		p, err = NewPostgres(sql)
+	case "sqlite3", "sqlite":
+		p, err = NewSQLite(sql)
 	default:
 		err = fmt.Errorf("sql parser: unsupported dialect %s", dialect)
 	}
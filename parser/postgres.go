@@ -0,0 +1,285 @@
+package parser
+
+import (
+	"errors"
+	"strings"
+	"unicode"
+
+	"github.com/xwb1989/sqlparser"
+)
+
+//postgres parse postgres sql by rewriting it to the mysql syntax,
+//the bindvars $n are rewritten to :vn, the double-quoted identifiers are rewritten to backticks,
+//the :: casts are removed, the RETURNING and ON CONFLICT clauses are split from the statement.
+//The || operator, ILIKE and the dollar-quoted strings have no mysql equivalent and are rejected.
+type postgres struct {
+	SQL       string
+	stmt      *mySQL
+	returning string
+}
+
+func NewPostgres(sql string) (*postgres, error) {
+	return newPostgres(sql, false)
+}
+
+func newPostgres(sql string, sqlite bool) (*postgres, error) {
+	rewritten, returning, err := rewritePostgres(sql, sqlite)
+	if err != nil {
+		return nil, err
+	}
+	stmt, err := NewMySQL(rewritten)
+	if err != nil {
+		return nil, err
+	}
+	return &postgres{
+		SQL:       sql,
+		stmt:      stmt,
+		returning: returning,
+	}, nil
+}
+
+func (p *postgres) Type() (Type, error) {
+	return p.stmt.Type()
+}
+
+func (p *postgres) SelectColumns() ([]*Column, error) {
+	return p.stmt.SelectColumns()
+}
+
+func (p *postgres) ReturningColumns() ([]*Column, error) {
+	if len(p.returning) == 0 {
+		return []*Column{}, nil
+	}
+	stmt, err := NewMySQL("SELECT " + p.returning)
+	if err != nil {
+		return []*Column{}, err
+	}
+	return stmt.SelectColumns()
+}
+
+//ColumnExpr the column is resolved from the rewritten statement and formatted in the postgres syntax
+func (p *postgres) ColumnExpr(column string) (string, error) {
+	colName, err := selectedColName(p.stmt.stmt, column)
+	if err != nil {
+		return "", err
+	}
+	return p.formatColName(colName), nil
+}
+
+//Keyset the keyset columns are resolved from the rewritten statement,
+//the original sql is rewritten to keep the postgres syntax
+func (p *postgres) Keyset(keyset *Keyset) (string, error) {
+	if _, err := keysetStmt(p.stmt.stmt); err != nil {
+		return "", err
+	}
+	exprs := make([]string, 0, len(keyset.Columns))
+	for _, column := range keyset.Columns {
+		expr, err := p.ColumnExpr(column)
+		if err != nil {
+			return "", err
+		}
+		exprs = append(exprs, expr)
+	}
+	return rewriteKeyset(p.SQL, exprs, keyset)
+}
+
+//References the columns of the RETURNING clause are referenced as the columns of the statement table
+func (p *postgres) References() (*References, error) {
+	refs, err := p.stmt.References()
+	if err != nil || len(p.returning) == 0 {
+		return refs, err
+	}
+	returning, err := NewMySQL("SELECT " + p.returning)
+	if err != nil {
+		return refs, err
+	}
+	returningRefs, err := returning.References()
+	if err != nil {
+		return refs, err
+	}
+	refs.Columns = append(refs.Columns, returningRefs.Columns...)
+	return refs, nil
+}
+
+//formatColName format the column name of the rewritten statement in the postgres syntax,
+//an identifier is double-quoted if it is double-quoted in the original sql to keep its case
+func (p *postgres) formatColName(colName *sqlparser.ColName) string {
+	name := p.formatIdent(colName.Name.String())
+	if colName.Qualifier.IsEmpty() {
+		return name
+	}
+	return p.formatIdent(colName.Qualifier.Name.String()) + "." + name
+}
+
+func (p *postgres) formatIdent(ident string) string {
+	quoted := `"` + strings.ReplaceAll(ident, `"`, `""`) + `"`
+	if strings.Contains(p.SQL, quoted) {
+		return quoted
+	}
+	return ident
+}
+
+//rewritePostgres rewrite the postgres sql to the mysql syntax,
+//returning is the rewritten column list of the top level RETURNING clause.
+//The sqlite sql shares the double-quoted identifiers, RETURNING and ON CONFLICT,
+//the $n bindvars and the :: casts are postgres only and rejected in sqlite.
+//The || concatenation of sqlite is rewritten to ^, which has the same precedence in the mysql syntax,
+//sqlite has no concat() before 3.44.
+func rewritePostgres(sql string, sqlite bool) (rewritten string, returning string, err error) {
+	var builder strings.Builder
+	builder.Grow(len(sql))
+	depth := 0
+	returningStart := -1
+	conflictStart := -1
+	runes := []rune(sql)
+	for i := 0; i < len(runes); i++ {
+		r := runes[i]
+		switch {
+		case r == '\'':
+			end := closeQuote(runes, i, '\'')
+			if end < 0 {
+				err = errors.New("sql parser: unterminated quoted string")
+				return
+			}
+			builder.WriteString(string(runes[i : end+1]))
+			i = end
+		case r == '"':
+			end := closeQuote(runes, i, '"')
+			if end < 0 {
+				err = errors.New("sql parser: unterminated quoted identifier")
+				return
+			}
+			ident := strings.ReplaceAll(string(runes[i+1:end]), `""`, `"`)
+			builder.WriteString("`" + strings.ReplaceAll(ident, "`", "``") + "`")
+			i = end
+		case r == '$' && i+1 < len(runes) && unicode.IsDigit(runes[i+1]):
+			if sqlite {
+				err = errors.New("sql parser: $n bindvars are not supported by sqlite, use ?")
+				return
+			}
+			builder.WriteString(":v")
+		case r == ':' && i+1 < len(runes) && runes[i+1] == ':':
+			if sqlite {
+				err = errors.New("sql parser: :: casts are not supported by sqlite, use CAST()")
+				return
+			}
+			i = skipCastType(runes, i+2) - 1
+		case r == '$' && !sqlite && i+1 < len(runes) && (runes[i+1] == '$' || isIdentStart(runes[i+1])) &&
+			(i == 0 || !isIdentPart(runes[i-1])):
+			err = errors.New("sql parser: dollar-quoted strings are not supported, use single-quoted strings")
+			return
+		case r == '|' && i+1 < len(runes) && runes[i+1] == '|':
+			//|| is parsed as OR in the mysql syntax
+			if !sqlite {
+				err = errors.New("sql parser: the || operator is not supported, use concat()")
+				return
+			}
+			builder.WriteRune('^')
+			i++
+		case r == '(':
+			depth++
+			builder.WriteRune(r)
+		case r == ')':
+			depth--
+			builder.WriteRune(r)
+		case isIdentStart(r) && (i == 0 || !isIdentPart(runes[i-1])):
+			end := i
+			for end < len(runes) && isIdentPart(runes[end]) {
+				end++
+			}
+			word := string(runes[i:end])
+			if strings.EqualFold(word, "ilike") {
+				err = errors.New("sql parser: ILIKE is not supported, use lower() with LIKE")
+				return
+			}
+			if depth == 0 && returningStart < 0 {
+				if strings.EqualFold(word, "returning") {
+					returningStart = builder.Len()
+				} else if conflictStart < 0 && strings.EqualFold(word, "on") &&
+					strings.EqualFold(nextWord(runes, end), "conflict") {
+					conflictStart = builder.Len()
+				}
+			}
+			builder.WriteString(word)
+			i = end - 1
+		default:
+			builder.WriteRune(r)
+		}
+	}
+
+	rewritten = builder.String()
+	if returningStart >= 0 {
+		returning = strings.TrimSpace(rewritten[returningStart+len("returning"):])
+		if len(returning) == 0 {
+			err = errors.New("sql parser: RETURNING without columns")
+			return
+		}
+		rewritten = rewritten[:returningStart]
+	}
+	if conflictStart >= 0 {
+		rewritten = rewritten[:conflictStart]
+	}
+	rewritten = strings.TrimSpace(rewritten)
+	return
+}
+
+//closeQuote return the index of the quote closing the one at start, doubled quotes are escaped quotes
+func closeQuote(runes []rune, start int, quote rune) int {
+	for i := start + 1; i < len(runes); i++ {
+		if runes[i] != quote {
+			continue
+		}
+		if i+1 < len(runes) && runes[i+1] == quote {
+			i++
+			continue
+		}
+		return i
+	}
+	return -1
+}
+
+//skipCastType return the index after the type of a :: cast, e.g. int, varchar(20), text[], "my_type"
+func skipCastType(runes []rune, start int) int {
+	i := start
+	for i < len(runes) && unicode.IsSpace(runes[i]) {
+		i++
+	}
+	if i < len(runes) && runes[i] == '"' {
+		if end := closeQuote(runes, i, '"'); end > 0 {
+			i = end + 1
+		}
+	}
+	for i < len(runes) && (isIdentPart(runes[i]) || runes[i] == '.') {
+		i++
+	}
+	if i < len(runes) && runes[i] == '(' {
+		for i < len(runes) && runes[i] != ')' {
+			i++
+		}
+		i++
+	}
+	for i+1 < len(runes) && runes[i] == '[' && runes[i+1] == ']' {
+		i += 2
+	}
+	return i
+}
+
+func nextWord(runes []rune, start int) string {
+	i := start
+	for i < len(runes) && unicode.IsSpace(runes[i]) {
+		i++
+	}
+	end := i
+	for end < len(runes) && isIdentPart(runes[end]) {
+		end++
+	}
+	return string(runes[i:end])
+}
+
+func isIdentStart(r rune) bool {
+	return r == '_' || unicode.IsLetter(r)
+}
+
+func isIdentPart(r rune) bool {
+	return r == '_' || unicode.IsLetter(r) || unicode.IsDigit(r)
+}
@@ -0,0 +1,303 @@
+package parser
+
+import (
+	"reflect"
+	"testing"
+)
+
+func Test_postgresParser_Type(t *testing.T) {
+	type fields struct {
+		SQL string
+	}
+	tests := []struct {
+		name    string
+		fields  fields
+		want    Type
+		wantErr bool
+	}{
+		{
+			name:    "Select",
+			fields:  fields{SQL: `SELECT * FROM "user" WHERE id = $1`},
+			want:    TypeSelect,
+			wantErr: false,
+		},
+		{
+			name:    "Insert Returning",
+			fields:  fields{SQL: `INSERT INTO "user"(name, age) VALUES ($1, $2) RETURNING id`},
+			want:    TypeInsert,
+			wantErr: false,
+		},
+		{
+			name: "Insert On Conflict",
+			fields: fields{SQL: `INSERT INTO "user"(id, name) VALUES ($1, $2) ` +
+				`ON CONFLICT (id) DO UPDATE SET name = EXCLUDED.name RETURNING id`},
+			want:    TypeInsert,
+			wantErr: false,
+		},
+		{
+			name:    "Update",
+			fields:  fields{SQL: `UPDATE "user" SET name = $1::varchar(20) WHERE id = $2`},
+			want:    TypeUpdate,
+			wantErr: false,
+		},
+		{
+			name:    "Delete",
+			fields:  fields{SQL: `DELETE FROM "user" WHERE id = $1 RETURNING *`},
+			want:    TypeDelete,
+			wantErr: false,
+		},
+		{
+			name:    "Unterminated Identifier",
+			fields:  fields{SQL: `SELECT * FROM "user WHERE id = $1`},
+			wantErr: true,
+		},
+		{
+			name:    "Concat",
+			fields:  fields{SQL: `SELECT * FROM "user" WHERE name LIKE CONCAT($1 ::text, '%')`},
+			want:    TypeSelect,
+			wantErr: false,
+		},
+		{
+			name:    "Concat Operator",
+			fields:  fields{SQL: `SELECT * FROM "user" WHERE name LIKE $1 || '%'`},
+			wantErr: true,
+		},
+		{
+			name:    "Concat Operator In String",
+			fields:  fields{SQL: `SELECT * FROM "user" WHERE name = 'a||b' AND id = $1`},
+			want:    TypeSelect,
+			wantErr: false,
+		},
+		{
+			name:    "ILike",
+			fields:  fields{SQL: `SELECT * FROM "user" WHERE name ILIKE $1`},
+			wantErr: true,
+		},
+		{
+			name:    "Not ILike",
+			fields:  fields{SQL: `SELECT * FROM "user" WHERE name NOT ilike $1`},
+			wantErr: true,
+		},
+		{
+			name:    "Dollar Quoted",
+			fields:  fields{SQL: `SELECT * FROM "user" WHERE name = $$a'b$$`},
+			wantErr: true,
+		},
+		{
+			name:    "Tagged Dollar Quoted",
+			fields:  fields{SQL: `SELECT * FROM "user" WHERE name = $tag$a'b$tag$`},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p, err := NewPostgres(tt.fields.SQL)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Type() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if err != nil {
+				return
+			}
+			got, err := p.Type()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Type() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("Type() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_postgresParser_SelectColumns(t *testing.T) {
+	type fields struct {
+		SQL string
+	}
+	tests := []struct {
+		name    string
+		fields  fields
+		want    []*Column
+		wantErr bool
+	}{
+		{
+			name:   "Quoted Identifier",
+			fields: fields{SQL: `SELECT "name", "u"."created_at" FROM "user" "u" WHERE id = $1`},
+			want: []*Column{
+				{
+					Alias:          "name",
+					TableQualifier: "",
+				},
+				{
+					Alias:          "created_at",
+					TableQualifier: "u",
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name:   "Cast",
+			fields: fields{SQL: `SELECT id::text, birthday::date AS "day", 'a::b' note FROM "user" WHERE id = $1::bigint`},
+			want: []*Column{
+				{
+					Alias:          "id",
+					TableQualifier: "",
+				},
+				{
+					Alias:          "day",
+					TableQualifier: "",
+				},
+				{
+					Alias:          "note",
+					TableQualifier: "",
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "Limit Offset",
+			fields: fields{
+				SQL: `SELECT * FROM "user" WHERE name LIKE CONCAT($1, '%') ORDER BY id LIMIT $2 OFFSET $3`,
+			},
+			want: []*Column{
+				{
+					Alias:          "*",
+					TableQualifier: "",
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name:    "Not Select",
+			fields:  fields{SQL: `DELETE FROM "user" WHERE id = $1`},
+			want:    []*Column{},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p, err := NewPostgres(tt.fields.SQL)
+			if err != nil {
+				t.Errorf("NewPostgres() error = %v", err)
+				return
+			}
+			got, err := p.SelectColumns()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("SelectColumns() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("SelectColumns() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_postgresParser_ReturningColumns(t *testing.T) {
+	type fields struct {
+		SQL string
+	}
+	tests := []struct {
+		name    string
+		fields  fields
+		want    []*Column
+		wantErr bool
+	}{
+		{
+			name:    "Without Returning",
+			fields:  fields{SQL: `INSERT INTO "user"(name) VALUES ($1)`},
+			want:    []*Column{},
+			wantErr: false,
+		},
+		{
+			name: "Returning",
+			fields: fields{SQL: `INSERT INTO "user"(name) VALUES ('returning') ` +
+				`RETURNING "id", created_at::date AS created_date`},
+			want: []*Column{
+				{
+					Alias:          "id",
+					TableQualifier: "",
+				},
+				{
+					Alias:          "created_date",
+					TableQualifier: "",
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "Returning After On Conflict",
+			fields: fields{SQL: `INSERT INTO "user"(id, name) VALUES ($1, $2) ` +
+				`ON CONFLICT (id) DO UPDATE SET name = EXCLUDED.name RETURNING id`},
+			want: []*Column{
+				{
+					Alias:          "id",
+					TableQualifier: "",
+				},
+			},
+			wantErr: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p, err := NewPostgres(tt.fields.SQL)
+			if err != nil {
+				t.Errorf("NewPostgres() error = %v", err)
+				return
+			}
+			got, err := p.ReturningColumns()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ReturningColumns() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ReturningColumns() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_postgresParser_Keyset(t *testing.T) {
+	type fields struct {
+		SQL string
+	}
+	tests := []struct {
+		name    string
+		fields  fields
+		keyset  *Keyset
+		want    string
+		wantErr bool
+	}{
+		{
+			name:   "Quoted Identifier",
+			fields: fields{SQL: `SELECT "u"."order", name FROM "user" AS "u" WHERE name = $1::text ORDER BY name`},
+			keyset: &Keyset{Columns: []string{"order"}, After: []string{"$2"}, Limit: "$3"},
+			want: `SELECT "u"."order", name FROM "user" AS "u" WHERE (name = $1::text) AND "u"."order" > $2 ` +
+				`ORDER BY "u"."order" LIMIT $3`,
+		},
+		{
+			name:    "Limit",
+			fields:  fields{SQL: `SELECT id FROM "user" LIMIT $1 OFFSET $2`},
+			keyset:  &Keyset{Columns: []string{"id"}, Limit: "$1"},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p, err := NewPostgres(tt.fields.SQL)
+			if err != nil {
+				t.Errorf("NewPostgres() error = %v", err)
+				return
+			}
+			got, err := p.Keyset(tt.keyset)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Keyset() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("Keyset() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,97 @@
+package parser
+
+import (
+	"strings"
+
+	"github.com/xwb1989/sqlparser"
+)
+
+//References the tables and the columns referenced by a statement, including the ones of its subqueries
+type References struct {
+	Tables  []*TableRef
+	Columns []*ColumnRef
+	//Aliases the aliases of the selected expressions, they can be referenced by ORDER BY, GROUP BY and HAVING
+	Aliases []string
+	//Selected the selected columns of the top level select in order,
+	//nil if the selected expression is not a column, e.g. count(*) AS total
+	Selected []*ColumnRef
+}
+
+//TableRef a table referenced by the FROM, JOIN, INSERT INTO, UPDATE or DELETE FROM clause
+type TableRef struct {
+	Name  string //empty if it is a derived table, e.g. (SELECT id FROM user) t
+	Alias string
+}
+
+//ColumnRef a column referenced by the statement, e.g. u.name
+type ColumnRef struct {
+	Qualifier string //the table name or alias, empty if the column is not qualified
+	Name      string
+}
+
+func references(stmt sqlparser.Statement) (*References, error) {
+	refs := &References{}
+	switch stmt := stmt.(type) {
+	case *sqlparser.Insert:
+		refs.Tables = append(refs.Tables, &TableRef{Name: stmt.Table.Name.String()})
+		for _, column := range stmt.Columns {
+			refs.Columns = append(refs.Columns, &ColumnRef{Name: column.String()})
+		}
+	case *sqlparser.Select:
+		for _, selectExpr := range stmt.SelectExprs {
+			var selected *ColumnRef
+			if expr, ok := selectExpr.(*sqlparser.AliasedExpr); ok {
+				if colName, ok := expr.Expr.(*sqlparser.ColName); ok {
+					selected = columnRef(colName)
+				}
+			}
+			refs.Selected = append(refs.Selected, selected)
+		}
+	}
+
+	err := sqlparser.Walk(func(node sqlparser.SQLNode) (kontinue bool, err error) {
+		switch node := node.(type) {
+		case *sqlparser.AliasedTableExpr:
+			table := &TableRef{Alias: node.As.String()}
+			if tableName, ok := node.Expr.(sqlparser.TableName); ok {
+				table.Name = tableName.Name.String()
+			}
+			refs.Tables = append(refs.Tables, table)
+		case *sqlparser.ColName:
+			refs.Columns = append(refs.Columns, columnRef(node))
+		case *sqlparser.AliasedExpr:
+			if !node.As.IsEmpty() {
+				refs.Aliases = append(refs.Aliases, node.As.String())
+			}
+		}
+		return true, nil
+	}, stmt)
+	return refs, err
+}
+
+func columnRef(colName *sqlparser.ColName) *ColumnRef {
+	return &ColumnRef{Qualifier: colName.Qualifier.Name.String(), Name: colName.Name.String()}
+}
+
+//table return the referenced table of the qualifier, it is the alias of the table if aliased, otherwise the name
+func (r *References) table(qualifier string) *TableRef {
+	for _, table := range r.Tables {
+		name := table.Alias
+		if len(name) == 0 {
+			name = table.Name
+		}
+		if strings.EqualFold(name, qualifier) {
+			return table
+		}
+	}
+	return nil
+}
+
+func (r *References) hasAlias(name string) bool {
+	for _, alias := range r.Aliases {
+		if strings.EqualFold(alias, name) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,548 @@
+package parser
+
+import (
+	"fmt"
+	"strings"
+)
+
+//Schema the tables defined by the DDL statements, e.g. a directory of CREATE TABLE statements or the migrations,
+//the statements are applied in order: CREATE TABLE, ALTER TABLE ADD, DROP, RENAME, MODIFY, CHANGE and ALTER COLUMN,
+//DROP TABLE and RENAME TABLE, the other statements are ignored.
+//The names are case-insensitive and the schema qualifiers are ignored, e.g. public.user is the table user
+type Schema struct {
+	tables map[string]*Table
+}
+
+//Table a table of the schema
+type Table struct {
+	Name    string
+	Columns []*TableColumn
+}
+
+//TableColumn a column of the table
+type TableColumn struct {
+	Name string
+	//Type the declared type in upper case, e.g. BIGINT, VARCHAR(64) or TIMESTAMP WITH TIME ZONE,
+	//it is empty if the type is omitted, which is allowed by sqlite
+	Type string
+}
+
+//ddlToken a word, a quoted identifier, a string literal or a punctuation of the DDL
+type ddlToken struct {
+	text   string
+	quoted bool //a quoted identifier or a string literal, it is never a keyword
+}
+
+//constraintKeywords the keywords ending the type of a column definition
+var constraintKeywords = []string{"NOT", "NULL", "PRIMARY", "DEFAULT", "UNIQUE", "REFERENCES", "CHECK",
+	"AUTO_INCREMENT", "AUTOINCREMENT", "COMMENT", "GENERATED", "CONSTRAINT", "COLLATE", "ON", "AS", "IDENTITY",
+	"CHARSET", "KEY", "FIRST", "AFTER", "INVISIBLE", "VISIBLE", "STORED", "VIRTUAL"}
+
+//tableConstraintKeywords the keywords starting a table constraint or index instead of a column definition
+var tableConstraintKeywords = []string{"CONSTRAINT", "PRIMARY", "UNIQUE", "KEY", "INDEX", "FOREIGN", "CHECK",
+	"FULLTEXT", "SPATIAL", "EXCLUDE", "LIKE", "PERIOD"}
+
+func NewSchema() *Schema {
+	return &Schema{tables: map[string]*Table{}}
+}
+
+//Table return the table by name, nil if it is not found
+func (s *Schema) Table(name string) *Table {
+	return s.tables[tableKey(name)]
+}
+
+//Column return the column by name, nil if it is not found
+func (t *Table) Column(name string) *TableColumn {
+	for _, column := range t.Columns {
+		if strings.EqualFold(column.Name, name) {
+			return column
+		}
+	}
+	return nil
+}
+
+//Validate return an error if a table or a column referenced by refs is not defined in the schema,
+//the columns of the derived tables and the selected aliases are not validated
+func (s *Schema) Validate(refs *References) error {
+	for _, table := range refs.Tables {
+		if len(table.Name) > 0 && s.Table(table.Name) == nil {
+			return fmt.Errorf("schema: table %s is not defined", table.Name)
+		}
+	}
+	for _, column := range refs.Columns {
+		if _, err := s.resolve(refs, column); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+//Resolve return the column of the schema referenced by column,
+//nil if it is not defined or not a table column, e.g. a column of a derived table or a selected alias
+func (s *Schema) Resolve(refs *References, column *ColumnRef) *TableColumn {
+	tableColumn, _ := s.resolve(refs, column)
+	return tableColumn
+}
+
+func (s *Schema) resolve(refs *References, column *ColumnRef) (*TableColumn, error) {
+	if len(column.Qualifier) > 0 {
+		table := refs.table(column.Qualifier)
+		if table == nil {
+			return nil, fmt.Errorf("schema: table %s of column %s.%s is not referenced",
+				column.Qualifier, column.Qualifier, column.Name)
+		}
+		if len(table.Name) == 0 {
+			return nil, nil
+		}
+		defined := s.Table(table.Name)
+		if defined == nil {
+			return nil, fmt.Errorf("schema: table %s is not defined", table.Name)
+		}
+		tableColumn := defined.Column(column.Name)
+		if tableColumn == nil {
+			return nil, fmt.Errorf("schema: column %s is not defined in table %s", column.Name, defined.Name)
+		}
+		return tableColumn, nil
+	}
+
+	derived := false
+	tableNames := make([]string, 0, len(refs.Tables))
+	for _, table := range refs.Tables {
+		if len(table.Name) == 0 {
+			derived = true
+			continue
+		}
+		tableNames = append(tableNames, table.Name)
+		if defined := s.Table(table.Name); defined != nil {
+			if tableColumn := defined.Column(column.Name); tableColumn != nil {
+				return tableColumn, nil
+			}
+		}
+	}
+	if derived || refs.hasAlias(column.Name) {
+		return nil, nil
+	}
+	return nil, fmt.Errorf("schema: column %s is not defined in table %s", column.Name, strings.Join(tableNames, ", "))
+}
+
+//Apply apply the DDL statements separated by semicolon to the schema
+func (s *Schema) Apply(ddl string) error {
+	tokens, err := ddlTokens(ddl)
+	if err != nil {
+		return err
+	}
+	start := 0
+	for i := 0; i <= len(tokens); i++ {
+		if i < len(tokens) && (tokens[i].quoted || tokens[i].text != ";") {
+			continue
+		}
+		if i > start {
+			if err = s.applyStmt(&ddlParser{tokens: tokens[start:i]}); err != nil {
+				return err
+			}
+		}
+		start = i + 1
+	}
+	return nil
+}
+
+func (s *Schema) applyStmt(p *ddlParser) error {
+	switch {
+	case p.keyword("CREATE"):
+		for p.keyword("TEMP") || p.keyword("TEMPORARY") || p.keyword("UNLOGGED") || p.keyword("GLOBAL") ||
+			p.keyword("LOCAL") {
+		}
+		if !p.keyword("TABLE") {
+			return nil
+		}
+		return s.createTable(p)
+	case p.keyword("ALTER", "TABLE"):
+		return s.alterTable(p)
+	case p.keyword("DROP", "TABLE"):
+		p.keyword("IF", "EXISTS")
+		for _, item := range p.items() {
+			delete(s.tables, tableKey((&ddlParser{tokens: item}).name()))
+		}
+	case p.keyword("RENAME", "TABLE"):
+		for _, item := range p.items() {
+			itemParser := &ddlParser{tokens: item}
+			from := itemParser.name()
+			if !itemParser.keyword("TO") {
+				return fmt.Errorf("schema: invalid RENAME TABLE %s", from)
+			}
+			if err := s.renameTable(from, itemParser.name()); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (s *Schema) createTable(p *ddlParser) error {
+	p.keyword("IF", "NOT", "EXISTS")
+	table := &Table{Name: p.name()}
+	if p.keyword("LIKE") {
+		like := s.Table(p.name())
+		if like == nil {
+			return fmt.Errorf("schema: table %s is created like an undefined table", table.Name)
+		}
+		table.Columns = append(table.Columns, like.Columns...)
+		s.tables[tableKey(table.Name)] = table
+		return nil
+	}
+	body, ok := p.parenthesized()
+	if !ok {
+		return fmt.Errorf("schema: table %s is created without the column definitions", table.Name)
+	}
+	for _, item := range (&ddlParser{tokens: body}).items() {
+		itemParser := &ddlParser{tokens: item}
+		if itemParser.isKeyword(tableConstraintKeywords...) {
+			continue
+		}
+		column, err := itemParser.columnDefinition()
+		if err != nil {
+			return fmt.Errorf("schema: %w of table %s", err, table.Name)
+		}
+		table.Columns = append(table.Columns, column)
+	}
+	s.tables[tableKey(table.Name)] = table
+	return nil
+}
+
+func (s *Schema) alterTable(p *ddlParser) error {
+	p.keyword("IF", "EXISTS")
+	p.keyword("ONLY")
+	name := p.name()
+	table := s.Table(name)
+	if table == nil {
+		return fmt.Errorf("schema: alter an undefined table %s", name)
+	}
+	for _, item := range p.items() {
+		action := &ddlParser{tokens: item}
+		switch {
+		case action.keyword("ADD"):
+			if action.isKeyword(tableConstraintKeywords...) {
+				continue
+			}
+			action.keyword("COLUMN")
+			action.keyword("IF", "NOT", "EXISTS")
+			column, err := action.columnDefinition()
+			if err != nil {
+				return fmt.Errorf("schema: %w of table %s", err, table.Name)
+			}
+			if table.Column(column.Name) == nil {
+				table.Columns = append(table.Columns, column)
+			}
+		case action.keyword("DROP"):
+			if action.isKeyword(tableConstraintKeywords...) {
+				continue
+			}
+			action.keyword("COLUMN")
+			action.keyword("IF", "EXISTS")
+			table.dropColumn(action.name())
+		case action.keyword("RENAME"):
+			if action.keyword("TO") || action.keyword("AS") {
+				if err := s.renameTable(table.Name, action.name()); err != nil {
+					return err
+				}
+				continue
+			}
+			action.keyword("COLUMN")
+			from := action.name()
+			if !action.keyword("TO") {
+				continue
+			}
+			if column := table.Column(from); column != nil {
+				column.Name = action.name()
+			}
+		case action.keyword("MODIFY"):
+			action.keyword("COLUMN")
+			column, err := action.columnDefinition()
+			if err != nil {
+				return fmt.Errorf("schema: %w of table %s", err, table.Name)
+			}
+			table.replaceColumn(column.Name, column)
+		case action.keyword("CHANGE"):
+			action.keyword("COLUMN")
+			from := action.name()
+			column, err := action.columnDefinition()
+			if err != nil {
+				return fmt.Errorf("schema: %w of table %s", err, table.Name)
+			}
+			table.replaceColumn(from, column)
+		case action.keyword("ALTER"):
+			action.keyword("COLUMN")
+			column := table.Column(action.name())
+			if column == nil {
+				continue
+			}
+			if action.keyword("TYPE") || action.keyword("SET", "DATA", "TYPE") {
+				column.Type = action.columnType()
+			}
+		}
+	}
+	return nil
+}
+
+func (s *Schema) renameTable(from, to string) error {
+	table := s.Table(from)
+	if table == nil {
+		return fmt.Errorf("schema: rename an undefined table %s", from)
+	}
+	delete(s.tables, tableKey(table.Name))
+	table.Name = to
+	s.tables[tableKey(to)] = table
+	return nil
+}
+
+//tableKey the lower case table name without the schema qualifier
+func tableKey(name string) string {
+	if dot := strings.LastIndexByte(name, '.'); dot >= 0 {
+		name = name[dot+1:]
+	}
+	return strings.ToLower(name)
+}
+
+func (t *Table) dropColumn(name string) {
+	for i, column := range t.Columns {
+		if strings.EqualFold(column.Name, name) {
+			t.Columns = append(t.Columns[:i:i], t.Columns[i+1:]...)
+			return
+		}
+	}
+}
+
+func (t *Table) replaceColumn(name string, column *TableColumn) {
+	for i, c := range t.Columns {
+		if strings.EqualFold(c.Name, name) {
+			t.Columns[i] = column
+			return
+		}
+	}
+}
+
+//ddlParser parse a DDL statement by the tokens
+type ddlParser struct {
+	tokens []ddlToken
+	pos    int
+}
+
+//isKeyword return true if the next token is one of the keywords
+func (p *ddlParser) isKeyword(keywords ...string) bool {
+	if p.pos >= len(p.tokens) || p.tokens[p.pos].quoted {
+		return false
+	}
+	for _, keyword := range keywords {
+		if strings.EqualFold(p.tokens[p.pos].text, keyword) {
+			return true
+		}
+	}
+	return false
+}
+
+//keyword skip the keywords and return true if the next tokens are the keywords in order
+func (p *ddlParser) keyword(keywords ...string) bool {
+	if p.pos+len(keywords) > len(p.tokens) {
+		return false
+	}
+	for i, keyword := range keywords {
+		token := p.tokens[p.pos+i]
+		if token.quoted || !strings.EqualFold(token.text, keyword) {
+			return false
+		}
+	}
+	p.pos += len(keywords)
+	return true
+}
+
+//name return the next identifier, the qualifiers are kept, e.g. public.user
+func (p *ddlParser) name() string {
+	var builder strings.Builder
+	for p.pos < len(p.tokens) {
+		builder.WriteString(p.tokens[p.pos].text)
+		p.pos++
+		if p.pos >= len(p.tokens) || p.tokens[p.pos].quoted || p.tokens[p.pos].text != "." {
+			break
+		}
+		builder.WriteByte('.')
+		p.pos++
+	}
+	return builder.String()
+}
+
+//parenthesized return the tokens in the next parentheses
+func (p *ddlParser) parenthesized() ([]ddlToken, bool) {
+	if p.pos >= len(p.tokens) || p.tokens[p.pos].quoted || p.tokens[p.pos].text != "(" {
+		return nil, false
+	}
+	depth := 0
+	for i := p.pos; i < len(p.tokens); i++ {
+		if p.tokens[i].quoted {
+			continue
+		}
+		switch p.tokens[i].text {
+		case "(":
+			depth++
+		case ")":
+			depth--
+			if depth == 0 {
+				body := p.tokens[p.pos+1 : i]
+				p.pos = i + 1
+				return body, true
+			}
+		}
+	}
+	return nil, false
+}
+
+//items return the remaining tokens split by the commas which are not in parentheses
+func (p *ddlParser) items() [][]ddlToken {
+	var items [][]ddlToken
+	depth, start := 0, p.pos
+	for i := p.pos; i <= len(p.tokens); i++ {
+		if i < len(p.tokens) {
+			if p.tokens[i].quoted {
+				continue
+			}
+			switch p.tokens[i].text {
+			case "(":
+				depth++
+				continue
+			case ")":
+				depth--
+				continue
+			case ",":
+				if depth > 0 {
+					continue
+				}
+			default:
+				continue
+			}
+		}
+		if i > start {
+			items = append(items, p.tokens[start:i])
+		}
+		start = i + 1
+	}
+	p.pos = len(p.tokens)
+	return items
+}
+
+//columnDefinition parse the column name and the type, the constraints are ignored
+func (p *ddlParser) columnDefinition() (*TableColumn, error) {
+	if p.pos >= len(p.tokens) {
+		return nil, fmt.Errorf("empty column definition")
+	}
+	column := &TableColumn{Name: p.tokens[p.pos].text}
+	p.pos++
+	column.Type = p.columnType()
+	return column, nil
+}
+
+//columnType return the type until the constraints, e.g. VARCHAR(64), DOUBLE PRECISION or INT[]
+func (p *ddlParser) columnType() string {
+	var builder strings.Builder
+	for p.pos < len(p.tokens) && !p.isKeyword(constraintKeywords...) {
+		token := p.tokens[p.pos]
+		if token.quoted {
+			break
+		}
+		if token.text == "(" || token.text == "[" {
+			body := token.text
+			if token.text == "(" {
+				tokens, _ := p.parenthesized()
+				texts := make([]string, 0, len(tokens))
+				for _, t := range tokens {
+					texts = append(texts, t.text)
+				}
+				body = "(" + strings.Join(texts, "") + ")"
+			} else {
+				p.pos++
+			}
+			builder.WriteString(body)
+			continue
+		}
+		if builder.Len() > 0 && token.text != "]" {
+			builder.WriteByte(' ')
+		}
+		builder.WriteString(strings.ToUpper(token.text))
+		p.pos++
+	}
+	return builder.String()
+}
+
+//ddlTokens split the DDL into tokens, the comments are skipped
+func ddlTokens(ddl string) ([]ddlToken, error) {
+	var tokens []ddlToken
+	for i := 0; i < len(ddl); {
+		c := ddl[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			i++
+		case c == '-' && strings.HasPrefix(ddl[i:], "--") || c == '#':
+			end := strings.IndexByte(ddl[i:], '\n')
+			if end < 0 {
+				return tokens, nil
+			}
+			i += end + 1
+		case c == '/' && strings.HasPrefix(ddl[i:], "/*"):
+			end := strings.Index(ddl[i+2:], "*/")
+			if end < 0 {
+				return nil, fmt.Errorf("schema: unterminated comment")
+			}
+			i += end + 4
+		case c == '\'' || c == '"' || c == '`':
+			end := i + 1
+			for ; end < len(ddl); end++ {
+				if ddl[end] != c {
+					continue
+				}
+				//the quote is escaped by doubling it
+				if end+1 < len(ddl) && ddl[end+1] == c {
+					end++
+					continue
+				}
+				break
+			}
+			if end >= len(ddl) {
+				return nil, fmt.Errorf("schema: unterminated quote %c", c)
+			}
+			text := strings.ReplaceAll(ddl[i+1:end], string([]byte{c, c}), string(c))
+			tokens = append(tokens, ddlToken{text: text, quoted: true})
+			i = end + 1
+		case c == '$' && dollarTag(ddl[i:]) != "":
+			//the postgres dollar-quoted string, e.g. the body of a function $$ ... $$
+			tag := dollarTag(ddl[i:])
+			end := strings.Index(ddl[i+len(tag):], tag)
+			if end < 0 {
+				return nil, fmt.Errorf("schema: unterminated quote %s", tag)
+			}
+			tokens = append(tokens, ddlToken{text: ddl[i+len(tag) : i+len(tag)+end], quoted: true})
+			i += len(tag) + end + len(tag)
+		case isWordByte(c) || c == '$':
+			end := i + 1
+			for end < len(ddl) && (isWordByte(ddl[end]) || ddl[end] == '$') {
+				end++
+			}
+			tokens = append(tokens, ddlToken{text: ddl[i:end]})
+			i = end
+		default:
+			tokens = append(tokens, ddlToken{text: string(c)})
+			i++
+		}
+	}
+	return tokens, nil
+}
+
+//dollarTag return the leading tag of a dollar-quoted string, e.g. $$ or $body$, empty if it is not a tag
+func dollarTag(ddl string) string {
+	for i := 1; i < len(ddl); i++ {
+		if ddl[i] == '$' {
+			return ddl[:i+1]
+		}
+		if !isWordByte(ddl[i]) || ('0' <= ddl[i] && ddl[i] <= '9' && i == 1) {
+			return ""
+		}
+	}
+	return ""
+}
@@ -0,0 +1,246 @@
+package parser
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSchema_Apply(t *testing.T) {
+	tests := []struct {
+		name    string
+		ddl     string
+		table   string
+		want    []*TableColumn
+		wantErr bool
+	}{
+		{
+			name: "MySQL",
+			ddl: "CREATE TABLE IF NOT EXISTS `user` (\n" +
+				"  `id` BIGINT UNSIGNED NOT NULL AUTO_INCREMENT, -- the id\n" +
+				"  `name` VARCHAR(64) CHARACTER SET utf8mb4 NOT NULL DEFAULT '',\n" +
+				"  `amount` DECIMAL(10, 2) COMMENT 'the amount; of money',\n" +
+				"  PRIMARY KEY (`id`),\n" +
+				"  UNIQUE KEY `uk_name` (`name`)\n" +
+				") ENGINE=InnoDB;",
+			table: "user",
+			want: []*TableColumn{
+				{Name: "id", Type: "BIGINT UNSIGNED"},
+				{Name: "name", Type: "VARCHAR(64) CHARACTER SET UTF8MB4"},
+				{Name: "amount", Type: "DECIMAL(10,2)"},
+			},
+		},
+		{
+			name: "Postgres",
+			ddl: `CREATE TABLE public."user" (id BIGSERIAL PRIMARY KEY, name CHARACTER VARYING(64) NOT NULL, ` +
+				`tags TEXT[], created_at TIMESTAMP WITH TIME ZONE DEFAULT now(), ` +
+				`CONSTRAINT fk_address FOREIGN KEY (address_id) REFERENCES address (id))`,
+			table: "USER",
+			want: []*TableColumn{
+				{Name: "id", Type: "BIGSERIAL"},
+				{Name: "name", Type: "CHARACTER VARYING(64)"},
+				{Name: "tags", Type: "TEXT[]"},
+				{Name: "created_at", Type: "TIMESTAMP WITH TIME ZONE"},
+			},
+		},
+		{
+			name: "Alter",
+			ddl: `CREATE TABLE user (id INTEGER PRIMARY KEY, name TEXT, mail TEXT, phone TEXT);
+				/* the migrations */
+				ALTER TABLE user ADD COLUMN birthday DATETIME;
+				ALTER TABLE user DROP COLUMN phone, RENAME COLUMN mail TO email;
+				ALTER TABLE user ALTER COLUMN name TYPE VARCHAR(64);
+				ALTER TABLE user MODIFY name VARCHAR(128) NOT NULL, CHANGE email mail VARCHAR(64);
+				CREATE INDEX idx_name ON user (name);`,
+			table: "user",
+			want: []*TableColumn{
+				{Name: "id", Type: "INTEGER"},
+				{Name: "name", Type: "VARCHAR(128)"},
+				{Name: "mail", Type: "VARCHAR(64)"},
+				{Name: "birthday", Type: "DATETIME"},
+			},
+		},
+		{
+			name: "Rename Table",
+			ddl: `CREATE TABLE users (id INTEGER, name);
+				ALTER TABLE users RENAME TO user;`,
+			table: "user",
+			want: []*TableColumn{
+				{Name: "id", Type: "INTEGER"},
+				{Name: "name"},
+			},
+		},
+		{
+			name: "Dollar Quoted Function",
+			ddl: `CREATE TABLE user (id INTEGER, updated_at TIMESTAMP);
+				CREATE FUNCTION touch() RETURNS trigger AS $$
+				BEGIN NEW.updated_at = now(); RETURN NEW; END;
+				$$ LANGUAGE plpgsql;
+				ALTER TABLE user ADD COLUMN name TEXT;`,
+			table: "user",
+			want: []*TableColumn{
+				{Name: "id", Type: "INTEGER"},
+				{Name: "updated_at", Type: "TIMESTAMP"},
+				{Name: "name", Type: "TEXT"},
+			},
+		},
+		{
+			name:  "Drop Table",
+			ddl:   "CREATE TABLE user (id INTEGER); DROP TABLE IF EXISTS user;",
+			table: "user",
+		},
+		{
+			name:    "Alter Undefined Table",
+			ddl:     "ALTER TABLE user ADD COLUMN name TEXT",
+			wantErr: true,
+		},
+		{
+			name:    "Unterminated Quote",
+			ddl:     "CREATE TABLE user (name TEXT DEFAULT 'a)",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := NewSchema()
+			err := s.Apply(tt.ddl)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Apply() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if tt.wantErr {
+				return
+			}
+			var got []*TableColumn
+			if table := s.Table(tt.table); table != nil {
+				got = table.Columns
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Apply() got = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSchema_Validate(t *testing.T) {
+	s := NewSchema()
+	err := s.Apply(`CREATE TABLE user (id INTEGER PRIMARY KEY, name TEXT, birthday DATETIME);
+		CREATE TABLE address (id INTEGER PRIMARY KEY, user_id INTEGER, city TEXT);`)
+	if err != nil {
+		t.Fatalf("Apply() error = %v", err)
+	}
+	tests := []struct {
+		name    string
+		dialect string
+		sql     string
+		wantErr bool
+	}{
+		{
+			name:    "Select Join",
+			dialect: "mysql",
+			sql: "SELECT u.id, u.name, a.city AS addr__city FROM user u JOIN address a ON a.user_id = u.id " +
+				"WHERE u.birthday > ? ORDER BY addr__city",
+		},
+		{
+			name:    "Subquery",
+			dialect: "sqlite3",
+			sql: `SELECT id, (SELECT count(*) FROM address a WHERE a.user_id = u.id) AS total FROM "user" u ` +
+				`WHERE id IN (SELECT user_id FROM address WHERE city = ?)`,
+		},
+		{
+			name:    "Derived Table",
+			dialect: "mysql",
+			sql:     "SELECT t.uid, city FROM (SELECT user_id AS uid, city FROM address) t",
+		},
+		{
+			name:    "Insert Returning",
+			dialect: "postgres",
+			sql:     `INSERT INTO "user" (name, birthday) VALUES ($1, $2) RETURNING id`,
+		},
+		{
+			name:    "Update",
+			dialect: "mysql",
+			sql:     "UPDATE user SET name = ? WHERE id = ?",
+		},
+		{
+			name:    "Undefined Table",
+			dialect: "mysql",
+			sql:     "SELECT id FROM users",
+			wantErr: true,
+		},
+		{
+			name:    "Undefined Column",
+			dialect: "mysql",
+			sql:     "SELECT u.id FROM user u WHERE u.mail = ?",
+			wantErr: true,
+		},
+		{
+			name:    "Undefined Unqualified Column",
+			dialect: "mysql",
+			sql:     "DELETE FROM address WHERE name = ?",
+			wantErr: true,
+		},
+		{
+			name:    "Unreferenced Qualifier",
+			dialect: "mysql",
+			sql:     "SELECT u.id FROM user WHERE id = ?",
+			wantErr: true,
+		},
+		{
+			name:    "Undefined Insert Column",
+			dialect: "postgres",
+			sql:     `INSERT INTO "user" (name, gender) VALUES ($1, $2)`,
+			wantErr: true,
+		},
+		{
+			name:    "Undefined Returning Column",
+			dialect: "sqlite3",
+			sql:     `INSERT INTO user (name) VALUES (?) RETURNING uid`,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p, err := New(tt.dialect, tt.sql)
+			if err != nil {
+				t.Fatalf("New() error = %v", err)
+			}
+			refs, err := p.References()
+			if err != nil {
+				t.Fatalf("References() error = %v", err)
+			}
+			if err = s.Validate(refs); (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestSchema_Resolve(t *testing.T) {
+	s := NewSchema()
+	err := s.Apply(`CREATE TABLE user (id INTEGER PRIMARY KEY, name TEXT);
+		CREATE TABLE address (id INTEGER PRIMARY KEY, user_id INTEGER, city TEXT);`)
+	if err != nil {
+		t.Fatalf("Apply() error = %v", err)
+	}
+	p, err := New("mysql", "SELECT u.id, city AS addr__city, count(*) AS total, t.x "+
+		"FROM user u JOIN address a ON a.user_id = u.id JOIN (SELECT 1 AS x) t GROUP BY u.id, city, t.x")
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	refs, err := p.References()
+	if err != nil {
+		t.Fatalf("References() error = %v", err)
+	}
+	var got []*TableColumn
+	for _, selected := range refs.Selected {
+		if selected == nil {
+			got = append(got, nil)
+			continue
+		}
+		got = append(got, s.Resolve(refs, selected))
+	}
+	want := []*TableColumn{{Name: "id", Type: "INTEGER"}, {Name: "city", Type: "TEXT"}, nil, nil}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Resolve() got = %+v, want %+v", got, want)
+	}
+}
@@ -0,0 +1,15 @@
+package parser
+
+//sqlite parse sqlite sql, double-quoted identifiers, RETURNING and ON CONFLICT are shared with postgres,
+//the postgres $n bindvars and :: casts are rejected
+type sqlite struct {
+	*postgres
+}
+
+func NewSQLite(sql string) (*sqlite, error) {
+	p, err := newPostgres(sql, true)
+	if err != nil {
+		return nil, err
+	}
+	return &sqlite{postgres: p}, nil
+}
@@ -0,0 +1,77 @@
+package parser
+
+import (
+	"testing"
+)
+
+func Test_sqliteParser_Type(t *testing.T) {
+	type fields struct {
+		SQL string
+	}
+	tests := []struct {
+		name    string
+		fields  fields
+		want    Type
+		wantErr bool
+	}{
+		{
+			name:    "Select",
+			fields:  fields{SQL: `SELECT * FROM "user" WHERE id = ?`},
+			want:    TypeSelect,
+			wantErr: false,
+		},
+		{
+			name:    "Insert Returning",
+			fields:  fields{SQL: `INSERT INTO "user"(name, age) VALUES (?, ?) RETURNING id`},
+			want:    TypeInsert,
+			wantErr: false,
+		},
+		{
+			name:    "Cast",
+			fields:  fields{SQL: `SELECT CAST(id AS CHAR) FROM "user" WHERE id = ?`},
+			want:    TypeSelect,
+			wantErr: false,
+		},
+		{
+			name:    "Concat Operator",
+			fields:  fields{SQL: `SELECT * FROM "user" WHERE name LIKE '%' || ? || '%' AND id = ?`},
+			want:    TypeSelect,
+			wantErr: false,
+		},
+		{
+			name:    "ILike",
+			fields:  fields{SQL: `SELECT * FROM "user" WHERE name ILIKE ?`},
+			wantErr: true,
+		},
+		{
+			name:    "Postgres Cast",
+			fields:  fields{SQL: `SELECT id::text FROM "user" WHERE id = ?`},
+			wantErr: true,
+		},
+		{
+			name:    "Postgres BindVar",
+			fields:  fields{SQL: `SELECT * FROM "user" WHERE id = $1`},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p, err := NewSQLite(tt.fields.SQL)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Type() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if err != nil {
+				return
+			}
+			got, err := p.Type()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Type() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("Type() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,224 @@
+package sqlmap
+
+import (
+	"fmt"
+	"go/types"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/gomelon/sqlmap/parser"
+)
+
+//the kinds of the column types to check the scan targets
+const (
+	columnKindInteger = "integer"
+	columnKindFloat   = "float"
+	columnKindBool    = "bool"
+	columnKindString  = "string"
+	columnKindBytes   = "bytes"
+	columnKindTime    = "time"
+	columnKindJSON    = "json"
+)
+
+//columnKinds the kinds of the column types by the first word of the type, the other types are not checked
+var columnKinds = map[string]string{
+	"INT": columnKindInteger, "INTEGER": columnKindInteger, "TINYINT": columnKindInteger,
+	"SMALLINT": columnKindInteger, "MEDIUMINT": columnKindInteger, "BIGINT": columnKindInteger,
+	"INT2": columnKindInteger, "INT4": columnKindInteger, "INT8": columnKindInteger, "SERIAL": columnKindInteger,
+	"SMALLSERIAL": columnKindInteger, "BIGSERIAL": columnKindInteger, "YEAR": columnKindInteger,
+	"REAL": columnKindFloat, "FLOAT": columnKindFloat, "FLOAT4": columnKindFloat, "FLOAT8": columnKindFloat,
+	"DOUBLE": columnKindFloat, "DECIMAL": columnKindFloat, "DEC": columnKindFloat, "NUMERIC": columnKindFloat,
+	"BOOL": columnKindBool, "BOOLEAN": columnKindBool,
+	"CHAR": columnKindString, "VARCHAR": columnKindString, "CHARACTER": columnKindString,
+	"NCHAR": columnKindString, "NVARCHAR": columnKindString, "TEXT": columnKindString,
+	"TINYTEXT": columnKindString, "MEDIUMTEXT": columnKindString, "LONGTEXT": columnKindString,
+	"CLOB": columnKindString, "CITEXT": columnKindString, "UUID": columnKindString, "ENUM": columnKindString,
+	"BLOB": columnKindBytes, "TINYBLOB": columnKindBytes, "MEDIUMBLOB": columnKindBytes,
+	"LONGBLOB": columnKindBytes, "BINARY": columnKindBytes, "VARBINARY": columnKindBytes, "BYTEA": columnKindBytes,
+	"DATE": columnKindTime, "DATETIME": columnKindTime, "TIME": columnKindTime, "TIMETZ": columnKindTime,
+	"TIMESTAMP": columnKindTime, "TIMESTAMPTZ": columnKindTime,
+	"JSON": columnKindJSON, "JSONB": columnKindJSON,
+}
+
+//schemaDownMarkers the markers of the down sections of the migration tools, e.g. goose and dbmate
+var schemaDownMarkers = []string{"-- +goose Down", "-- migrate:down"}
+
+//WithSchema validate the queries against the tables defined by the DDL files when generating,
+//the referenced tables and columns must be defined, the selected columns must be scannable into the result fields.
+//A path is a .sql file or a directory of them, e.g. the migrations, the files of a directory are applied by name,
+//the *.down.sql files and the down sections of goose and dbmate are skipped.
+//A relative path is relative to the directory of the generating package
+func WithSchema(paths ...string) Option {
+	return func(f *functions) {
+		f.schemaPaths = append(f.schemaPaths, paths...)
+	}
+}
+
+//loadSchema load the schema of the options, the schema is nil if there is no schema path
+func (f *functions) loadSchema() error {
+	if f.schema != nil || len(f.schemaPaths) == 0 {
+		return nil
+	}
+	schema := parser.NewSchema()
+	for _, schemaPath := range f.schemaPaths {
+		if dir := f.pkgParser.Path(f.pkgPath); !filepath.IsAbs(schemaPath) && len(dir) > 0 {
+			schemaPath = filepath.Join(dir, schemaPath)
+		}
+		files, err := schemaFiles(schemaPath)
+		if err != nil {
+			return fmt.Errorf("load schema fail: %w,path=%s", err, schemaPath)
+		}
+		for _, file := range files {
+			ddl, err := os.ReadFile(file)
+			if err != nil {
+				return fmt.Errorf("load schema fail: %w,path=%s", err, file)
+			}
+			if err = schema.Apply(upDDL(string(ddl))); err != nil {
+				return fmt.Errorf("load schema fail: %w,path=%s", err, file)
+			}
+		}
+	}
+	f.schema = schema
+	return nil
+}
+
+//schemaFiles return the file of path, or the .sql files of the directory path sorted by name
+func schemaFiles(path string) ([]string, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+	if !info.IsDir() {
+		return []string{path}, nil
+	}
+	entries, err := os.ReadDir(path)
+	if err != nil {
+		return nil, err
+	}
+	files := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || !strings.HasSuffix(name, ".sql") || strings.HasSuffix(name, ".down.sql") {
+			continue
+		}
+		files = append(files, filepath.Join(path, name))
+	}
+	return files, nil
+}
+
+//upDDL cut the down section of a migration
+func upDDL(ddl string) string {
+	for _, marker := range schemaDownMarkers {
+		if i := strings.Index(ddl, marker); i >= 0 {
+			ddl = ddl[:i]
+		}
+	}
+	return ddl
+}
+
+//checkSchema return an error if a table or a column referenced by the sql is not defined in the schema
+func (f *functions) checkSchema(method types.Object, mapper *Mapper, sql string) error {
+	if f.schema == nil {
+		return nil
+	}
+	sqlParser, err := parser.New(f.Dialect(mapper), sql)
+	if err != nil {
+		return f.validateError(method, err, sql)
+	}
+	refs, err := sqlParser.References()
+	if err == nil {
+		err = f.schema.Validate(refs)
+	}
+	if err != nil {
+		return f.validateError(method, err, sql)
+	}
+	return nil
+}
+
+//checkScanTypes return an error if a selected column of the schema can not be scanned into its target,
+//the targets unmarshalled from json or scanned through a converter are not checked
+func (f *functions) checkScanTypes(method types.Object, sqlParser parser.Parser, targets []*scanTarget,
+	sql string) error {
+
+	if f.schema == nil {
+		return nil
+	}
+	refs, err := sqlParser.References()
+	if err != nil {
+		return f.validateError(method, err, sql)
+	}
+	if len(refs.Selected) != len(targets) {
+		return nil
+	}
+	for i, target := range targets {
+		if refs.Selected[i] == nil || target.json || f.converter(target.typ).scan != nil {
+			continue
+		}
+		column := f.schema.Resolve(refs, refs.Selected[i])
+		if column == nil {
+			continue
+		}
+		if !f.scannable(columnKind(column.Type), target.typ) {
+			err = fmt.Errorf("column %s of type %s can not be scanned into %s of type %s",
+				column.Name, column.Type, target.expr, target.typ.String())
+			return f.validateError(method, err, sql)
+		}
+	}
+	return nil
+}
+
+//scannable return true if the column of the kind can be scanned into the type,
+//the unknown kinds and the sql.Scanner implementations are always scannable
+func (f *functions) scannable(kind string, typ types.Type) bool {
+	typ = unalias(typ)
+	if pointer, ok := typ.(*types.Pointer); ok {
+		typ = unalias(pointer.Elem())
+	}
+	if len(kind) == 0 || f.hasMethod(typ, "Scan") {
+		return true
+	}
+	if named, ok := typ.(*types.Named); ok {
+		obj := named.Obj()
+		if obj.Pkg() != nil && obj.Pkg().Path() == "time" && obj.Name() == "Time" {
+			return kind == columnKindTime
+		}
+	}
+	switch typ := typ.Underlying().(type) {
+	case *types.Basic:
+		info := typ.Info()
+		switch {
+		case info&types.IsBoolean != 0, info&types.IsInteger != 0:
+			return kind == columnKindInteger || kind == columnKindBool
+		case info&types.IsFloat != 0:
+			return kind == columnKindInteger || kind == columnKindFloat
+		}
+	case *types.Slice:
+		if types.Identical(typ.Elem(), types.Typ[types.Byte]) {
+			return kind != columnKindInteger && kind != columnKindFloat && kind != columnKindBool
+		}
+	}
+	return true
+}
+
+//columnKind return the kind of the column type by its first word, e.g. integer of BIGINT UNSIGNED,
+//empty if the type is unknown or an array
+func columnKind(columnType string) string {
+	if strings.Contains(columnType, "[]") {
+		return ""
+	}
+	word := columnType
+	if end := strings.IndexAny(word, " ("); end >= 0 {
+		word = word[:end]
+	}
+	return columnKinds[word]
+}
+
+//validateError the error of validating the sql against the schema with the position of the method
+func (f *functions) validateError(method types.Object, err error, sql string) error {
+	var pos string
+	if pkg := f.pkgParser.Package(f.pkgPath); pkg != nil && pkg.Fset != nil {
+		pos = pkg.Fset.Position(method.Pos()).String()
+	}
+	return fmt.Errorf("validate sql fail: %w, method=[%s],pos=%s,sql=%s", err, method.String(), pos, sql)
+}
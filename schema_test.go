@@ -0,0 +1,56 @@
+package sqlmap
+
+import "testing"
+
+func Test_columnKind(t *testing.T) {
+	tests := []struct {
+		name       string
+		columnType string
+		want       string
+	}{
+		{name: "Integer", columnType: "BIGINT UNSIGNED", want: columnKindInteger},
+		{name: "Decimal", columnType: "DECIMAL(10,2)", want: columnKindFloat},
+		{name: "Varchar", columnType: "CHARACTER VARYING(64)", want: columnKindString},
+		{name: "Timestamp", columnType: "TIMESTAMP WITH TIME ZONE", want: columnKindTime},
+		{name: "Array", columnType: "INT[]"},
+		{name: "Omitted", columnType: ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := columnKind(tt.columnType); got != tt.want {
+				t.Errorf("columnKind() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_upDDL(t *testing.T) {
+	tests := []struct {
+		name string
+		ddl  string
+		want string
+	}{
+		{
+			name: "Goose",
+			ddl:  "-- +goose Up\nCREATE TABLE user (id INTEGER);\n-- +goose Down\nDROP TABLE user;\n",
+			want: "-- +goose Up\nCREATE TABLE user (id INTEGER);\n",
+		},
+		{
+			name: "Dbmate",
+			ddl:  "-- migrate:up\nCREATE TABLE user (id INTEGER);\n-- migrate:down\nDROP TABLE user;\n",
+			want: "-- migrate:up\nCREATE TABLE user (id INTEGER);\n",
+		},
+		{
+			name: "Plain",
+			ddl:  "CREATE TABLE user (id INTEGER);",
+			want: "CREATE TABLE user (id INTEGER);",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := upDDL(tt.ddl); got != tt.want {
+				t.Errorf("upDDL() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,75 @@
+package multiple
+
+import (
+	"context"
+	"time"
+
+	"github.com/gomelon/melon/data/query"
+	"github.com/gomelon/sqlmap/dao"
+)
+
+//User 用户信息
+type User struct {
+	Id        int64
+	Name      string
+	Birthday  time.Time
+	CreatedAt time.Time
+}
+
+//UserDao the mapper of the default dialect
+//+sqlmap.Mapper Table="user"
+type UserDao interface {
+	FindById(ctx context.Context, id int64) (*User, error)
+
+	FindByNameContains(ctx context.Context, name string) ([]*User, error)
+
+	Insert(ctx context.Context, user *User) (*User, error)
+
+	FindByNameOrderById(ctx context.Context, name string, pager query.Pager) (*dao.Page[*User], error)
+
+	//FindByNameContainsOrderByName
+	/*+sqlmap.Select Cursor="id"*/
+	FindByNameContainsOrderByName(ctx context.Context, name string, cursor *dao.CursorRequest) (
+		*dao.CursorPage[*User], error)
+
+	DeleteById(ctx context.Context, id int64) (int64, error)
+}
+
+//UserPostgresDao
+//+sqlmap.Mapper Table="user" Dialect="postgres"
+type UserPostgresDao interface {
+	FindById(ctx context.Context, id int64) (*User, error)
+
+	FindByNameContains(ctx context.Context, name string) ([]*User, error)
+
+	ExistsById(ctx context.Context, id int64) (bool, error)
+
+	FindByNameOrderById(ctx context.Context, name string, pager query.Pager) (*dao.Page[*User], error)
+
+	FindByBirthdayGTE(ctx context.Context, birthday time.Time, pager *query.PageRequest) (*dao.Slice[*User], error)
+
+	FindByNameContainsOrderById(ctx context.Context, name string, sorts []*query.Sort) ([]*User, error)
+
+	//FindByName
+	/*+sqlmap.Select Query="select u.id, u.name, u.created_at from public.user u where u.name = :name order by u.id" Cursor="created_at DESC,id DESC"*/
+	FindByName(ctx context.Context, name string, cursor dao.CursorRequest) (*dao.CursorPage[*User], error)
+
+	FindByIdInOrderById(ctx context.Context, ids []int64) ([]*User, error)
+
+	//Search
+	/*+sqlmap.Select Query="select * from public.user {{where}} {{if .name}} name = :name {{end}} {{if .birthday}} or birthday < :birthday {{end}} {{end}}"*/
+	Search(ctx context.Context, name string, birthday *time.Time) ([]*User, error)
+
+	Insert(ctx context.Context, user *User) (*User, error)
+
+	/*+sqlmap.Insert ConsecutiveIds=true*/
+	InsertBatch(ctx context.Context, users []*User) (int64, error)
+
+	//Upsert
+	/*+sqlmap.Upsert ConflictKeys="id"*/
+	Upsert(ctx context.Context, user *User) (*User, error)
+
+	UpdateNameById(ctx context.Context, name string, id int64) (int64, error)
+
+	DeleteById(ctx context.Context, id int64) (int64, error)
+}
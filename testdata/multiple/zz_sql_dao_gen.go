@@ -0,0 +1,575 @@
+// Code generated by meta. DO NOT EDIT.
+
+package multiple
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gomelon/melon/data/query"
+	"github.com/gomelon/sqlmap/dao"
+)
+
+var _ UserDao = &UserDaoSQLImpl{}
+
+//meta:data source=UserDao tags=,dao,struct
+type UserDaoSQLImpl struct {
+	_router dao.Router
+}
+
+// NewUserDaoSQLImpl UserDaoSQLImpl provider
+// +autowire.Provider
+//
+//meta:data source=UserDao tags=mysql,dao,provider
+func NewUserDaoSQLImpl(_router dao.Router) *UserDaoSQLImpl {
+	return &UserDaoSQLImpl{
+		_router: _router,
+	}
+}
+
+func (_impl *UserDaoSQLImpl) DeleteById(ctx context.Context, id int64) (int64, error) {
+	_sql := "DELETE FROM `user` WHERE (`id` = ?)"
+	_result, err := _impl._router.Primary(ctx).
+		ExecContext(ctx, _sql, id)
+	if err != nil {
+		return 0, err
+	}
+	return _result.RowsAffected()
+}
+
+func (_impl *UserDaoSQLImpl) FindById(ctx context.Context, id int64) (*User, error) {
+	_sql := "SELECT id, name, birthday, created_at FROM `user` WHERE (`id` = ?)"
+	_rows, _err := _impl._router.Replica(ctx).
+		QueryContext(ctx, _sql, id)
+
+	var _item *User
+	if _err != nil {
+		return _item, _err
+	}
+
+	defer _rows.Close()
+
+	if !_rows.Next() {
+		return _item, _rows.Err()
+	}
+
+	_item = &User{}
+	_err = _rows.Scan(&_item.Id, &_item.Name, &_item.Birthday, &_item.CreatedAt)
+	return _item, _err
+}
+
+func (_impl *UserDaoSQLImpl) FindByNameContains(ctx context.Context, name string) ([]*User, error) {
+	_sql := "SELECT id, name, birthday, created_at FROM `user` WHERE (`name` LIKE CONCAT('%',?,'%'))"
+	_rows, _err := _impl._router.Replica(ctx).
+		QueryContext(ctx, _sql, name)
+
+	var _items []*User
+	if _err != nil {
+		return _items, _err
+	}
+
+	defer _rows.Close()
+
+	for _rows.Next() {
+		_item := &User{}
+		_err = _rows.Scan(&_item.Id, &_item.Name, &_item.Birthday, &_item.CreatedAt)
+		if _err != nil {
+			return _items, _err
+		}
+		_items = append(_items, _item)
+	}
+	return _items, _rows.Err()
+}
+
+func (_impl *UserDaoSQLImpl) FindByNameContainsOrderByName(ctx context.Context, name string, cursor *dao.CursorRequest) (*dao.CursorPage[*User], error) {
+	if cursor.Limit <= 0 {
+		return nil, dao.ErrInvalidLimit
+	}
+	_sql := "SELECT id, name, birthday, created_at FROM `user` WHERE (`name` LIKE CONCAT('%',?,'%')) ORDER BY id LIMIT ?"
+	_args := []any{name, cursor.Limit + 1}
+	if len(cursor.After) > 0 {
+		var _afterId int64
+		if _err := dao.DecodeCursor(cursor.After, &_afterId); _err != nil {
+			return nil, _err
+		}
+		_sql = "SELECT id, name, birthday, created_at FROM `user` WHERE (`name` LIKE CONCAT('%',?,'%')) AND id > ? ORDER BY id LIMIT ?"
+		_args = []any{name, _afterId, cursor.Limit + 1}
+	}
+	_rows, _err := _impl._router.Replica(ctx).
+		QueryContext(ctx, _sql, _args...)
+	if _err != nil {
+		return nil, _err
+	}
+
+	defer _rows.Close()
+
+	var _items []*User
+	for _rows.Next() {
+		_item := &User{}
+		_err = _rows.Scan(&_item.Id, &_item.Name, &_item.Birthday, &_item.CreatedAt)
+		if _err != nil {
+			return nil, _err
+		}
+		_items = append(_items, _item)
+	}
+	if _err = _rows.Err(); _err != nil {
+		return nil, _err
+	}
+	return dao.NewCursorPage(_items, cursor.Limit, func(_item *User) []any {
+		return []any{_item.Id}
+	})
+}
+
+func (_impl *UserDaoSQLImpl) FindByNameOrderById(ctx context.Context, name string, pager query.Pager) (*dao.Page[*User], error) {
+	_sql := "SELECT id, name, birthday, created_at FROM `user` WHERE (`name` = ?) ORDER BY `id` ASC LIMIT ?, ?"
+	_rows, _err := _impl._router.Replica(ctx).
+		QueryContext(ctx, _sql, name, pager.Offset(), pager.PageSize())
+	if _err != nil {
+		return nil, _err
+	}
+
+	defer _rows.Close()
+
+	var _items []*User
+	for _rows.Next() {
+		_item := &User{}
+		_err = _rows.Scan(&_item.Id, &_item.Name, &_item.Birthday, &_item.CreatedAt)
+		if _err != nil {
+			return nil, _err
+		}
+		_items = append(_items, _item)
+	}
+	if _err = _rows.Err(); _err != nil {
+		return nil, _err
+	}
+
+	_page := &dao.Page[*User]{Items: _items, Page: pager.Page(), PageSize: pager.PageSize()}
+	_page.Total = int64(pager.Offset() + len(_items))
+	//count the rows when the page is full or out of range
+	if len(_items) == pager.PageSize() || (len(_items) == 0 && pager.Offset() > 0) {
+		_countSQL := "SELECT COUNT(*) FROM (SELECT id, name, birthday, created_at FROM `user` WHERE (`name` = ?) ORDER BY `id` ASC) AS _page"
+		_err = _impl._router.Replica(ctx).
+			QueryRowContext(ctx, _countSQL, name).Scan(&_page.Total)
+		if _err != nil {
+			return nil, _err
+		}
+	}
+	return _page, nil
+}
+
+func (_impl *UserDaoSQLImpl) Insert(ctx context.Context, user *User) (*User, error) {
+	_sql := "INSERT INTO `user` (`name`, `birthday`, `created_at`) VALUES (?, ?, ?)"
+	_result, _err := _impl._router.Primary(ctx).
+		ExecContext(ctx, _sql, user.Name, user.Birthday, user.CreatedAt)
+	if _err != nil {
+		return nil, _err
+	}
+	_id, _err := _result.LastInsertId()
+	if _err != nil {
+		return nil, _err
+	}
+	user.Id = _id
+	return user, nil
+}
+
+var _ UserPostgresDao = &UserPostgresDaoSQLImpl{}
+
+//meta:data source=UserPostgresDao tags=postgres,dao,struct
+type UserPostgresDaoSQLImpl struct {
+	_router dao.Router
+}
+
+// NewUserPostgresDaoSQLImpl UserPostgresDaoSQLImpl provider
+// +autowire.Provider
+//
+//meta:data source=UserPostgresDao tags=postgres,dao,provider
+func NewUserPostgresDaoSQLImpl(_router dao.Router) *UserPostgresDaoSQLImpl {
+	return &UserPostgresDaoSQLImpl{
+		_router: _router,
+	}
+}
+
+func (_impl *UserPostgresDaoSQLImpl) DeleteById(ctx context.Context, id int64) (int64, error) {
+	_sql := "DELETE FROM \"user\" WHERE (\"id\" = $1)"
+	_result, err := _impl._router.Primary(ctx).
+		ExecContext(ctx, _sql, id)
+	if err != nil {
+		return 0, err
+	}
+	return _result.RowsAffected()
+}
+
+func (_impl *UserPostgresDaoSQLImpl) ExistsById(ctx context.Context, id int64) (bool, error) {
+	_sql := "SELECT 1 AS X FROM \"user\" WHERE (\"id\" = $1) LIMIT 1 OFFSET 0"
+	_rows, _err := _impl._router.Replica(ctx).
+		QueryContext(ctx, _sql, id)
+
+	var _item bool
+	if _err != nil {
+		return _item, _err
+	}
+
+	defer _rows.Close()
+
+	if !_rows.Next() {
+		return _item, _rows.Err()
+	}
+
+	_err = _rows.Scan(&_item)
+	return _item, _err
+}
+
+func (_impl *UserPostgresDaoSQLImpl) FindByBirthdayGTE(ctx context.Context, birthday time.Time, pager *query.PageRequest) (*dao.Slice[*User], error) {
+	_sql := "SELECT id, name, birthday, created_at FROM \"user\" WHERE (\"birthday\" >= $1) LIMIT $2 OFFSET $3"
+	_rows, _err := _impl._router.Replica(ctx).
+		QueryContext(ctx, _sql, birthday, pager.PageSize()+1, pager.Offset())
+	if _err != nil {
+		return nil, _err
+	}
+
+	defer _rows.Close()
+
+	var _items []*User
+	for _rows.Next() {
+		_item := &User{}
+		_err = _rows.Scan(&_item.Id, &_item.Name, &_item.Birthday, &_item.CreatedAt)
+		if _err != nil {
+			return nil, _err
+		}
+		_items = append(_items, _item)
+	}
+	if _err = _rows.Err(); _err != nil {
+		return nil, _err
+	}
+
+	_slice := &dao.Slice[*User]{Items: _items, Page: pager.Page(), PageSize: pager.PageSize()}
+	if len(_items) > pager.PageSize() {
+		_slice.Items, _slice.HasNext = _items[:pager.PageSize()], true
+	}
+	return _slice, nil
+}
+
+func (_impl *UserPostgresDaoSQLImpl) FindById(ctx context.Context, id int64) (*User, error) {
+	_sql := "SELECT id, name, birthday, created_at FROM \"user\" WHERE (\"id\" = $1)"
+	_rows, _err := _impl._router.Replica(ctx).
+		QueryContext(ctx, _sql, id)
+
+	var _item *User
+	if _err != nil {
+		return _item, _err
+	}
+
+	defer _rows.Close()
+
+	if !_rows.Next() {
+		return _item, _rows.Err()
+	}
+
+	_item = &User{}
+	_err = _rows.Scan(&_item.Id, &_item.Name, &_item.Birthday, &_item.CreatedAt)
+	return _item, _err
+}
+
+func (_impl *UserPostgresDaoSQLImpl) FindByIdInOrderById(ctx context.Context, ids []int64) ([]*User, error) {
+	_builder := dao.NewSQLBuilder(dao.BindDollar)
+	_builder.Write("SELECT id, name, birthday, created_at FROM \"user\" WHERE (\"id\" in (")
+	dao.In(_builder, ids)
+	_builder.Write(")) ORDER BY \"id\" ASC")
+	_sql, _args := _builder.SQL(), _builder.Args()
+	_rows, _err := _impl._router.Replica(ctx).
+		QueryContext(ctx, _sql, _args...)
+
+	var _items []*User
+	if _err != nil {
+		return _items, _err
+	}
+
+	defer _rows.Close()
+
+	for _rows.Next() {
+		_item := &User{}
+		_err = _rows.Scan(&_item.Id, &_item.Name, &_item.Birthday, &_item.CreatedAt)
+		if _err != nil {
+			return _items, _err
+		}
+		_items = append(_items, _item)
+	}
+	return _items, _rows.Err()
+}
+
+func (_impl *UserPostgresDaoSQLImpl) FindByName(ctx context.Context, name string, cursor dao.CursorRequest) (*dao.CursorPage[*User], error) {
+	if cursor.Limit <= 0 {
+		return nil, dao.ErrInvalidLimit
+	}
+	_sql := "select u.id, u.name, u.created_at from public.user u where u.name = $1 ORDER BY u.created_at DESC, u.id DESC LIMIT $2"
+	_args := []any{name, cursor.Limit + 1}
+	if len(cursor.After) > 0 {
+		var _afterCreatedAt time.Time
+		var _afterId int64
+		if _err := dao.DecodeCursor(cursor.After, &_afterCreatedAt, &_afterId); _err != nil {
+			return nil, _err
+		}
+		_sql = "select u.id, u.name, u.created_at from public.user u WHERE (u.name = $1) AND (u.created_at, u.id) < ($2, $3) ORDER BY u.created_at DESC, u.id DESC LIMIT $4"
+		_args = []any{name, _afterCreatedAt, _afterId, cursor.Limit + 1}
+	}
+	_rows, _err := _impl._router.Replica(ctx).
+		QueryContext(ctx, _sql, _args...)
+	if _err != nil {
+		return nil, _err
+	}
+
+	defer _rows.Close()
+
+	var _items []*User
+	for _rows.Next() {
+		_item := &User{}
+		_err = _rows.Scan(&_item.Id, &_item.Name, &_item.CreatedAt)
+		if _err != nil {
+			return nil, _err
+		}
+		_items = append(_items, _item)
+	}
+	if _err = _rows.Err(); _err != nil {
+		return nil, _err
+	}
+	return dao.NewCursorPage(_items, cursor.Limit, func(_item *User) []any {
+		return []any{_item.CreatedAt, _item.Id}
+	})
+}
+
+func (_impl *UserPostgresDaoSQLImpl) FindByNameContains(ctx context.Context, name string) ([]*User, error) {
+	_sql := "SELECT id, name, birthday, created_at FROM \"user\" WHERE (\"name\" LIKE CONCAT('%', $1 ::text, '%'))"
+	_rows, _err := _impl._router.Replica(ctx).
+		QueryContext(ctx, _sql, name)
+
+	var _items []*User
+	if _err != nil {
+		return _items, _err
+	}
+
+	defer _rows.Close()
+
+	for _rows.Next() {
+		_item := &User{}
+		_err = _rows.Scan(&_item.Id, &_item.Name, &_item.Birthday, &_item.CreatedAt)
+		if _err != nil {
+			return _items, _err
+		}
+		_items = append(_items, _item)
+	}
+	return _items, _rows.Err()
+}
+
+func (_impl *UserPostgresDaoSQLImpl) FindByNameContainsOrderById(ctx context.Context, name string, sorts []*query.Sort) ([]*User, error) {
+	_sql := "SELECT id, name, birthday, created_at FROM \"user\" WHERE (\"name\" LIKE CONCAT('%', $1 ::text, '%')) ORDER BY \"id\" ASC"
+	if len(sorts) > 0 {
+		_orderBy, _err := dao.OrderBy(sorts, map[string]string{
+			"Birthday":   "birthday",
+			"CreatedAt":  "created_at",
+			"Id":         "\"id\"",
+			"Name":       "\"name\"",
+			"birthday":   "birthday",
+			"created_at": "created_at",
+			"id":         "\"id\"",
+			"name":       "\"name\"",
+		})
+		if _err != nil {
+			return nil, _err
+		}
+		_sql = "SELECT id, name, birthday, created_at FROM \"user\" WHERE (\"name\" LIKE CONCAT('%', $1 ::text, '%')) " + _orderBy
+	}
+	_rows, _err := _impl._router.Replica(ctx).
+		QueryContext(ctx, _sql, name)
+
+	var _items []*User
+	if _err != nil {
+		return _items, _err
+	}
+
+	defer _rows.Close()
+
+	for _rows.Next() {
+		_item := &User{}
+		_err = _rows.Scan(&_item.Id, &_item.Name, &_item.Birthday, &_item.CreatedAt)
+		if _err != nil {
+			return _items, _err
+		}
+		_items = append(_items, _item)
+	}
+	return _items, _rows.Err()
+}
+
+func (_impl *UserPostgresDaoSQLImpl) FindByNameOrderById(ctx context.Context, name string, pager query.Pager) (*dao.Page[*User], error) {
+	_sql := "SELECT id, name, birthday, created_at FROM \"user\" WHERE (\"name\" = $1) ORDER BY \"id\" ASC LIMIT $2 OFFSET $3"
+	_rows, _err := _impl._router.Replica(ctx).
+		QueryContext(ctx, _sql, name, pager.PageSize(), pager.Offset())
+	if _err != nil {
+		return nil, _err
+	}
+
+	defer _rows.Close()
+
+	var _items []*User
+	for _rows.Next() {
+		_item := &User{}
+		_err = _rows.Scan(&_item.Id, &_item.Name, &_item.Birthday, &_item.CreatedAt)
+		if _err != nil {
+			return nil, _err
+		}
+		_items = append(_items, _item)
+	}
+	if _err = _rows.Err(); _err != nil {
+		return nil, _err
+	}
+
+	_page := &dao.Page[*User]{Items: _items, Page: pager.Page(), PageSize: pager.PageSize()}
+	_page.Total = int64(pager.Offset() + len(_items))
+	//count the rows when the page is full or out of range
+	if len(_items) == pager.PageSize() || (len(_items) == 0 && pager.Offset() > 0) {
+		_countSQL := "SELECT COUNT(*) FROM (SELECT id, name, birthday, created_at FROM \"user\" WHERE (\"name\" = $1) ORDER BY \"id\" ASC) AS _page"
+		_err = _impl._router.Replica(ctx).
+			QueryRowContext(ctx, _countSQL, name).Scan(&_page.Total)
+		if _err != nil {
+			return nil, _err
+		}
+	}
+	return _page, nil
+}
+
+func (_impl *UserPostgresDaoSQLImpl) Insert(ctx context.Context, user *User) (*User, error) {
+	_sql := "INSERT INTO \"user\" (\"name\", \"birthday\", \"created_at\") VALUES ($1, $2, $3) RETURNING \"id\""
+	var _id int64
+	_err := _impl._router.Primary(ctx).
+		QueryRowContext(ctx, _sql, user.Name, user.Birthday, user.CreatedAt).Scan(&_id)
+	if _err != nil {
+		return nil, _err
+	}
+	user.Id = _id
+	return user, nil
+}
+
+func (_impl *UserPostgresDaoSQLImpl) InsertBatch(ctx context.Context, users []*User) (int64, error) {
+	_db := _impl._router.Primary(ctx)
+	var _rowsAffected int64
+	for _start := 0; _start < len(users); _start += 21845 {
+		_end := _start + 21845
+		if _end > len(users) {
+			_end = len(users)
+		}
+		_sqlBuilder := strings.Builder{}
+		_sqlBuilder.WriteString("INSERT INTO \"user\" (\"name\", \"birthday\", \"created_at\") VALUES ")
+		_args := make([]any, 0, (_end-_start)*3)
+		for _i, _item := range users[_start:_end] {
+			if _i > 0 {
+				_sqlBuilder.WriteString(", ")
+			}
+			_sqlBuilder.WriteString("($" + strconv.Itoa(_i*3+1) + ", $" + strconv.Itoa(_i*3+2) + ", $" + strconv.Itoa(_i*3+3) + ")")
+			_args = append(_args, _item.Name, _item.Birthday, _item.CreatedAt)
+		}
+		_sqlBuilder.WriteString(" RETURNING \"id\"")
+		_rows, _err := _db.QueryContext(ctx, _sqlBuilder.String(), _args...)
+		if _err != nil {
+			return _rowsAffected, _err
+		}
+		_i := _start
+		for ; _rows.Next(); _i++ {
+			if _i >= _end {
+				_rows.Close()
+				return _rowsAffected, dao.ErrReturnedRows
+			}
+			if _err = _rows.Scan(&users[_i].Id); _err != nil {
+				_rows.Close()
+				return _rowsAffected, _err
+			}
+			_rowsAffected++
+		}
+		_rows.Close()
+		if _err = _rows.Err(); _err != nil {
+			return _rowsAffected, _err
+		}
+		if _i != _end {
+			return _rowsAffected, dao.ErrReturnedRows
+		}
+	}
+	return _rowsAffected, nil
+}
+
+func (_impl *UserPostgresDaoSQLImpl) Search(ctx context.Context, name string, birthday *time.Time) ([]*User, error) {
+	_builder := dao.NewSQLBuilder(dao.BindDollar)
+	_builder.Write("select id, name, birthday, created_at from public.user ")
+	_builder.Begin(dao.ClauseWhere)
+	_builder.Write(" ")
+	if len(name) > 0 {
+		_builder.Write(" name = ")
+		_builder.Arg(name)
+		_builder.Write(" ")
+	}
+	_builder.Write(" ")
+	if birthday != nil {
+		_builder.Write(" or birthday < ")
+		_builder.Arg(birthday)
+		_builder.Write(" ")
+	}
+	_builder.Write(" ")
+	_builder.End()
+	_sql, _args := _builder.SQL(), _builder.Args()
+	_rows, _err := _impl._router.Replica(ctx).
+		QueryContext(ctx, _sql, _args...)
+
+	var _items []*User
+	if _err != nil {
+		return _items, _err
+	}
+
+	defer _rows.Close()
+
+	for _rows.Next() {
+		_item := &User{}
+		_err = _rows.Scan(&_item.Id, &_item.Name, &_item.Birthday, &_item.CreatedAt)
+		if _err != nil {
+			return _items, _err
+		}
+		_items = append(_items, _item)
+	}
+	return _items, _rows.Err()
+}
+
+func (_impl *UserPostgresDaoSQLImpl) UpdateNameById(ctx context.Context, name string, id int64) (int64, error) {
+	_sql := "UPDATE \"user\" SET \"name\" = $1 WHERE (\"id\" = $2)"
+	_result, _err := _impl._router.Primary(ctx).
+		ExecContext(ctx, _sql, name, id)
+	if _err != nil {
+		return 0, _err
+	}
+	return _result.RowsAffected()
+}
+
+func (_impl *UserPostgresDaoSQLImpl) Upsert(ctx context.Context, user *User) (*User, error) {
+	_builder := dao.NewSQLBuilder(dao.BindDollar)
+	_builder.Write("INSERT INTO \"user\" (")
+	if user.Id != 0 {
+		_builder.Write("\"id\", ")
+	}
+	_builder.Write("\"name\", \"birthday\", \"created_at\") VALUES (")
+	if user.Id != 0 {
+		_builder.Arg(user.Id)
+		_builder.Write(", ")
+	}
+	_builder.Arg(user.Name)
+	_builder.Write(", ")
+	_builder.Arg(user.Birthday)
+	_builder.Write(", ")
+	_builder.Arg(user.CreatedAt)
+	_builder.Write(") ON CONFLICT (\"id\") DO UPDATE SET \"name\" = EXCLUDED.\"name\", \"birthday\" = EXCLUDED.\"birthday\", \"created_at\" = EXCLUDED.\"created_at\" RETURNING \"id\"")
+	_sql, _args := _builder.SQL(), _builder.Args()
+	var _id int64
+	_err := _impl._router.Primary(ctx).
+		QueryRowContext(ctx, _sql, _args...).Scan(&_id)
+	if _err != nil {
+		return nil, _err
+	}
+	user.Id = _id
+	return user, nil
+}
@@ -0,0 +1,10 @@
+CREATE TABLE user
+(
+    id         INTEGER PRIMARY KEY AUTOINCREMENT,
+    name       TEXT    NOT NULL,
+    gender     INTEGER NOT NULL,
+    birthday   DATETIME,
+    tags       TEXT    NOT NULL DEFAULT '',
+    profile    TEXT,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP
+);
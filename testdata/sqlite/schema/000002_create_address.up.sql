@@ -0,0 +1,9 @@
+CREATE TABLE address
+(
+    id      INTEGER PRIMARY KEY AUTOINCREMENT,
+    user_id INTEGER NOT NULL,
+    phone   TEXT    NOT NULL,
+    city    TEXT    NOT NULL
+);
+
+CREATE INDEX idx_address_user_id ON address (user_id);
@@ -0,0 +1 @@
+ALTER TABLE user ADD COLUMN mail TEXT;
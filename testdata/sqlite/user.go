@@ -0,0 +1,239 @@
+package sqlite
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/gomelon/melon/data/query"
+	"github.com/gomelon/sqlmap/dao"
+)
+
+//Gender 性别
+type Gender uint8
+
+//Tags 标签, stored as a comma separated text
+type Tags []string
+
+//ParseTags
+//+sqlmap.Converter
+func ParseTags(s string) Tags {
+	if len(s) == 0 {
+		return nil
+	}
+	return strings.Split(s, ",")
+}
+
+//FormatTags
+//+sqlmap.Converter
+func FormatTags(tags Tags) (string, error) {
+	for _, tag := range tags {
+		if strings.Contains(tag, ",") {
+			return "", errors.New("tag can not contain comma")
+		}
+	}
+	return strings.Join(tags, ","), nil
+}
+
+//Profile 用户资料, stored as a json column
+type Profile struct {
+	Nickname string   `json:"nickname"`
+	Hobbies  []string `json:"hobbies,omitempty"`
+}
+
+//User 用户信息
+type User struct {
+	Id        int64
+	Name      string
+	Gender    Gender
+	Birthday  time.Time
+	Email     string `db:"mail"`
+	Tags      Tags
+	Profile   *Profile  `db:"profile,json"`
+	CreatedAt time.Time `db:",omitempty"`
+	Remark    string    `db:"-"`
+}
+
+//BaseModel 公共字段
+type BaseModel struct {
+	Id        int64
+	CreatedAt time.Time
+}
+
+//Address 地址
+type Address struct {
+	Phone string
+	City  string `db:"city,nullable"`
+}
+
+//UserDetail 用户详情
+type UserDetail struct {
+	BaseModel
+	Name    string
+	Address Address `db:"addr"`
+}
+
+//UserFilter 用户查询条件
+type UserFilter struct {
+	Name     string
+	Genders  []Gender
+	Birthday time.Time `db:"born_after"`
+	Address  Address   `db:"addr"`
+}
+
+//UserDao
+//+sqlmap.Mapper Table="user" Dialect="sqlite"
+type UserDao interface {
+	FindById(ctx context.Context, id int64) (*User, error)
+
+	FindByBirthdayGTE(ctx context.Context, time time.Time) ([]*User, error)
+
+	FindByNameContains(ctx context.Context, name string) ([]*User, error)
+
+	FindByEmail(ctx context.Context, email string) (*User, error)
+
+	//FindDetailById
+	/*+sqlmap.Select Query="select u.id, u.name, u.created_at, a.phone as addr__phone, a.city as addr__city from user u inner join address a on a.user_id = u.id where u.id = :id"*/
+	FindDetailById(ctx context.Context, id int64) (*UserDetail, error)
+
+	//FindDetails
+	/*+sqlmap.Select Query="select u.*, a.city addr__city from user u inner join address a on a.user_id = u.id"*/
+	FindDetails(ctx context.Context) ([]*UserDetail, error)
+
+	//FindDetailsWithCity
+	/*+sqlmap.Select Query="select u.id, u.name, a.city as addr__city from user u left join address a on a.user_id = u.id order by u.id"*/
+	FindDetailsWithCity(ctx context.Context) ([]*UserDetail, error)
+
+	//FindDetailsWithAddress
+	/*+sqlmap.Select Query="select u.id, u.name, u.created_at, a.phone as addr__phone, a.city as addr__city from user u left join address a on a.user_id = u.id order by u.id" Nullable*/
+	FindDetailsWithAddress(ctx context.Context) ([]*UserDetail, error)
+
+	//FindMaxGenderByName
+	/*+sqlmap.Select Query="select max(gender) as gender from user where name = :name" Nullable*/
+	FindMaxGenderByName(ctx context.Context, name string) (Gender, error)
+
+	//FindByMail2
+	/*+sqlmap.Select Query="select id, name, mail from user where mail = ?"*/
+	FindByMail2(ctx context.Context, email string) (*User, error)
+
+	//FindRowById
+	/*+sqlmap.Select Query="select id, name, gender from user where id = :id"*/
+	FindRowById(ctx context.Context, id int64) (map[string]any, error)
+
+	//CountGroupByGender
+	/*+sqlmap.Select Query="select gender, count(*) as total from user group by gender order by gender"*/
+	CountGroupByGender(ctx context.Context) ([]map[string]any, error)
+
+	//FindByGender
+	/*+sqlmap.Select MapKey="id"*/
+	FindByGender(ctx context.Context, gender Gender) (map[int64]*User, error)
+
+	//FindDetailsByName
+	/*+sqlmap.Select Query="select u.id, u.name, a.phone as addr__phone from user u left join address a on a.user_id = u.id where u.name = :name" MapKey="addr__phone" Nullable*/
+	FindDetailsByName(ctx context.Context, name string) (map[string]UserDetail, error)
+
+	QueryByGenderOrderById(ctx context.Context, gender Gender) (*dao.Rows[*User], error)
+
+	//FindNames
+	/*+sqlmap.Select Query="select name from user order by id"*/
+	FindNames(ctx context.Context) (*dao.Rows[string], error)
+
+	FindByBirthdayLT(ctx context.Context, birthday time.Time, fn func(*User) error) error
+
+	//FindDetailsByGender
+	/*+sqlmap.Select Query="select u.id, u.name, a.city as addr__city from user u left join address a on a.user_id = u.id where u.gender = :gender order by u.id"*/
+	FindDetailsByGender(ctx context.Context, gender Gender, fn func(UserDetail) error) error
+
+	FindByBirthdayGTEOrderById(ctx context.Context, birthday time.Time, pager query.Pager) (*dao.Page[*User], error)
+
+	//FindNamesByGender
+	/*+sqlmap.Select Query="select name from user where gender = :gender order by id"*/
+	FindNamesByGender(ctx context.Context, gender Gender, pager query.Pager) (*dao.Slice[string], error)
+
+	FindByGenderOrderByName(ctx context.Context, gender Gender, sorts []*query.Sort) ([]*User, error)
+
+	//FindDetailsSorted
+	/*+sqlmap.Select Query="select u.id, u.name, a.city as addr__city from user u left join address a on a.user_id = u.id"*/
+	FindDetailsSorted(ctx context.Context, sorts []*query.Sort) ([]*UserDetail, error)
+
+	FindByBirthdayGTEOrderByName(ctx context.Context, birthday time.Time, pager query.Pager, sorts []*query.Sort) (
+		*dao.Page[*User], error)
+
+	//QueryByGender
+	/*+sqlmap.Select Cursor="birthday DESC,id DESC"*/
+	QueryByGender(ctx context.Context, gender Gender, cursor dao.CursorRequest) (*dao.CursorPage[*User], error)
+
+	//FindDetailsAfter
+	/*+sqlmap.Select Query="select u.id, u.name, a.city as addr__city from user u left join address a on a.user_id = u.id where u.birthday >= :birthday order by u.name" Cursor="id"*/
+	FindDetailsAfter(ctx context.Context, birthday time.Time, cursor *dao.CursorRequest) (
+		*dao.CursorPage[*UserDetail], error)
+
+	//FindNamesAfter
+	/*+sqlmap.Select Query="select id, name from user" Cursor="name,id"*/
+	FindNamesAfter(ctx context.Context, cursor dao.CursorRequest) (*dao.CursorPage[*User], error)
+
+	//FindIdsAfter
+	/*+sqlmap.Select Query="select id from user" Cursor="id"*/
+	FindIdsAfter(ctx context.Context, cursor dao.CursorRequest) (*dao.CursorPage[int64], error)
+
+	//Search
+	/*+sqlmap.Select Query="select * from user {{where}} {{if .name}} and name like :name {{end}} {{if .gender}} and gender = :gender {{end}} {{if .birthday}} and birthday >= :birthday {{end}} {{end}} order by id"*/
+	Search(ctx context.Context, name string, gender Gender, birthday time.Time) ([]*User, error)
+
+	FindByIdIn(ctx context.Context, ids []int64) ([]*User, error)
+
+	//FindNamesByGenders
+	/*+sqlmap.Select Query="select name from user where gender in (:genders) and name <> :name order by id"*/
+	FindNamesByGenders(ctx context.Context, genders []Gender, name string) ([]string, error)
+
+	//FindNamesByIdNotIn
+	/*+sqlmap.Select Query="select name from user where id not in (:ids) order by id"*/
+	FindNamesByIdNotIn(ctx context.Context, ids []int64) ([]string, error)
+
+	//FindByFilter
+	/*+sqlmap.Select Query="select * from user {{where}} {{if .f.Name}} and name like :f.Name {{end}} {{if .f.Genders}} and gender in (:f.Genders) {{end}} {{if .born_after}} and birthday >= :born_after {{end}} {{end}} order by id"*/
+	FindByFilter(ctx context.Context, f *UserFilter) ([]*User, error)
+
+	//CountByFilter
+	/*+sqlmap.Select Query="select count(*) as total from user u left join address a on a.user_id = u.id where u.birthday >= :born_after and a.city = :filter.Address.City"*/
+	CountByFilter(ctx context.Context, filter UserFilter) (int, error)
+
+	//FindByKeyword
+	/*+sqlmap.Select Query="select * from user where (name = :keyword or mail = :keyword) and gender = :gender order by id"*/
+	FindByKeyword(ctx context.Context, gender Gender, keyword string) ([]*User, error)
+
+	ExistsById(ctx context.Context, id int64) (bool, error)
+
+	CountByBirthdayGTE(ctx context.Context, time time.Time) (int, error)
+
+	Insert(ctx context.Context, user *User) (*User, error)
+
+	//InsertBatch
+	/*+sqlmap.Insert ConsecutiveIds=true*/
+	InsertBatch(ctx context.Context, users []*User) ([]*User, error)
+
+	SaveBatch(ctx context.Context, users []*User) ([]*User, error)
+
+	//InsertForId
+	/*+sqlmap.Insert LastInsertId=true*/
+	InsertForId(ctx context.Context, user *User) (int64, error)
+
+	InsertCount(ctx context.Context, user *User) (validId int64, err error)
+
+	//Upsert
+	/*+sqlmap.Upsert ConflictKeys="id" UpdateColumns="name,gender"*/
+	Upsert(ctx context.Context, user *User) (*User, error)
+
+	UpdateById(ctx context.Context, id int64, user *User) (int64, error)
+
+	UpdateNameAndGenderById(ctx context.Context, name string, gender Gender, id int64) (int64, error)
+
+	UpdateEmailById(ctx context.Context, email string, id int64) (int64, error)
+
+	//UpdateSelective
+	/*+sqlmap.Update Query="update user {{set}} {{if .user.Name}} name = :user.Name, {{end}} {{if .user.Email}} mail = :mail, {{end}} {{end}} where id = :id"*/
+	UpdateSelective(ctx context.Context, id int64, user *User) (int64, error)
+
+	DeleteById(ctx context.Context, id int64) (int64, error)
+}
@@ -0,0 +1,1489 @@
+// Code generated by meta. DO NOT EDIT.
+
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+	"time"
+
+	"github.com/gomelon/melon/data/query"
+	"github.com/gomelon/sqlmap/dao"
+)
+
+var _ UserDao = &UserDaoSQLImpl{}
+
+//meta:data source=UserDao tags=sqlite,dao,struct
+type UserDaoSQLImpl struct {
+	_router dao.Router
+}
+
+// NewUserDaoSQLImpl UserDaoSQLImpl provider
+// +autowire.Provider
+//
+//meta:data source=UserDao tags=sqlite3,dao,provider
+func NewUserDaoSQLImpl(_router dao.Router) *UserDaoSQLImpl {
+	return &UserDaoSQLImpl{
+		_router: _router,
+	}
+}
+
+func (_impl *UserDaoSQLImpl) CountByBirthdayGTE(ctx context.Context, time time.Time) (int, error) {
+	_sql := "SELECT COUNT(*) AS X FROM \"user\" WHERE (\"birthday\" >= ?)"
+	_rows, _err := _impl._router.Replica(ctx).
+		QueryContext(ctx, _sql, time)
+
+	var _item int
+	if _err != nil {
+		return _item, _err
+	}
+
+	defer _rows.Close()
+
+	if !_rows.Next() {
+		return _item, _rows.Err()
+	}
+
+	_err = _rows.Scan(&_item)
+	return _item, _err
+}
+
+func (_impl *UserDaoSQLImpl) CountByFilter(ctx context.Context, filter UserFilter) (int, error) {
+	_sql := "select count(*) as total from user u left join address a on a.user_id = u.id where u.birthday >= ? and a.city = ?"
+	_rows, _err := _impl._router.Replica(ctx).
+		QueryContext(ctx, _sql, filter.Birthday, filter.Address.City)
+
+	var _item int
+	if _err != nil {
+		return _item, _err
+	}
+
+	defer _rows.Close()
+
+	if !_rows.Next() {
+		return _item, _rows.Err()
+	}
+
+	_err = _rows.Scan(&_item)
+	return _item, _err
+}
+
+func (_impl *UserDaoSQLImpl) CountGroupByGender(ctx context.Context) ([]map[string]any, error) {
+	_sql := "select gender, count(*) as total from user group by gender order by gender"
+	_rows, _err := _impl._router.Replica(ctx).
+		QueryContext(ctx, _sql)
+
+	var _items []map[string]any
+	if _err != nil {
+		return _items, _err
+	}
+
+	defer _rows.Close()
+
+	for _rows.Next() {
+		_item, _err := dao.ScanMap(_rows)
+		if _err != nil {
+			return _items, _err
+		}
+		_items = append(_items, _item)
+	}
+	return _items, _rows.Err()
+}
+
+func (_impl *UserDaoSQLImpl) DeleteById(ctx context.Context, id int64) (int64, error) {
+	_sql := "DELETE FROM \"user\" WHERE (\"id\" = ?)"
+	_result, err := _impl._router.Primary(ctx).
+		ExecContext(ctx, _sql, id)
+	if err != nil {
+		return 0, err
+	}
+	return _result.RowsAffected()
+}
+
+func (_impl *UserDaoSQLImpl) ExistsById(ctx context.Context, id int64) (bool, error) {
+	_sql := "SELECT 1 AS X FROM \"user\" WHERE (\"id\" = ?) LIMIT 1 OFFSET 0"
+	_rows, _err := _impl._router.Replica(ctx).
+		QueryContext(ctx, _sql, id)
+
+	var _item bool
+	if _err != nil {
+		return _item, _err
+	}
+
+	defer _rows.Close()
+
+	if !_rows.Next() {
+		return _item, _rows.Err()
+	}
+
+	_err = _rows.Scan(&_item)
+	return _item, _err
+}
+
+func (_impl *UserDaoSQLImpl) FindByBirthdayGTE(ctx context.Context, time time.Time) ([]*User, error) {
+	_sql := "SELECT id, name, gender, birthday, mail, tags, profile, created_at FROM \"user\" WHERE (\"birthday\" >= ?)"
+	_rows, _err := _impl._router.Replica(ctx).
+		QueryContext(ctx, _sql, time)
+
+	var _items []*User
+	if _err != nil {
+		return _items, _err
+	}
+
+	defer _rows.Close()
+
+	for _rows.Next() {
+		_item := &User{}
+		var _convTags string
+		var _jsonProfile []byte
+		_err = _rows.Scan(&_item.Id, &_item.Name, &_item.Gender, &_item.Birthday, &_item.Email, &_convTags, &_jsonProfile, &_item.CreatedAt)
+		if _err != nil {
+			return _items, _err
+		}
+		_item.Tags = ParseTags(_convTags)
+		if _err = dao.UnmarshalJSON(_jsonProfile, &_item.Profile); _err != nil {
+			return _items, _err
+		}
+		_items = append(_items, _item)
+	}
+	return _items, _rows.Err()
+}
+
+func (_impl *UserDaoSQLImpl) FindByBirthdayGTEOrderById(ctx context.Context, birthday time.Time, pager query.Pager) (*dao.Page[*User], error) {
+	_sql := "SELECT id, name, gender, birthday, mail, tags, profile, created_at FROM \"user\" WHERE (\"birthday\" >= ?) ORDER BY \"id\" ASC LIMIT ? OFFSET ?"
+	_rows, _err := _impl._router.Replica(ctx).
+		QueryContext(ctx, _sql, birthday, pager.PageSize(), pager.Offset())
+	if _err != nil {
+		return nil, _err
+	}
+
+	defer _rows.Close()
+
+	var _items []*User
+	for _rows.Next() {
+		_item := &User{}
+		var _convTags string
+		var _jsonProfile []byte
+		_err = _rows.Scan(&_item.Id, &_item.Name, &_item.Gender, &_item.Birthday, &_item.Email, &_convTags, &_jsonProfile, &_item.CreatedAt)
+		if _err != nil {
+			return nil, _err
+		}
+		_item.Tags = ParseTags(_convTags)
+		if _err = dao.UnmarshalJSON(_jsonProfile, &_item.Profile); _err != nil {
+			return nil, _err
+		}
+		_items = append(_items, _item)
+	}
+	if _err = _rows.Err(); _err != nil {
+		return nil, _err
+	}
+
+	_page := &dao.Page[*User]{Items: _items, Page: pager.Page(), PageSize: pager.PageSize()}
+	_page.Total = int64(pager.Offset() + len(_items))
+	//count the rows when the page is full or out of range
+	if len(_items) == pager.PageSize() || (len(_items) == 0 && pager.Offset() > 0) {
+		_countSQL := "SELECT COUNT(*) FROM (SELECT id, name, gender, birthday, mail, tags, profile, created_at FROM \"user\" WHERE (\"birthday\" >= ?) ORDER BY \"id\" ASC) AS _page"
+		_err = _impl._router.Replica(ctx).
+			QueryRowContext(ctx, _countSQL, birthday).Scan(&_page.Total)
+		if _err != nil {
+			return nil, _err
+		}
+	}
+	return _page, nil
+}
+
+func (_impl *UserDaoSQLImpl) FindByBirthdayGTEOrderByName(ctx context.Context, birthday time.Time, pager query.Pager, sorts []*query.Sort) (*dao.Page[*User], error) {
+	_sql := "SELECT id, name, gender, birthday, mail, tags, profile, created_at FROM \"user\" WHERE (\"birthday\" >= ?) ORDER BY \"name\" ASC LIMIT ? OFFSET ?"
+	if len(sorts) > 0 {
+		_orderBy, _err := dao.OrderBy(sorts, map[string]string{
+			"Birthday":   "\"birthday\"",
+			"CreatedAt":  "created_at",
+			"Email":      "mail",
+			"Gender":     "gender",
+			"Id":         "id",
+			"Name":       "\"name\"",
+			"Profile":    "profile",
+			"Tags":       "tags",
+			"birthday":   "\"birthday\"",
+			"created_at": "created_at",
+			"gender":     "gender",
+			"id":         "id",
+			"mail":       "mail",
+			"name":       "\"name\"",
+			"profile":    "profile",
+			"tags":       "tags",
+		})
+		if _err != nil {
+			return nil, _err
+		}
+		_sql = "SELECT id, name, gender, birthday, mail, tags, profile, created_at FROM \"user\" WHERE (\"birthday\" >= ?) " + _orderBy + " LIMIT ? OFFSET ?"
+	}
+	_rows, _err := _impl._router.Replica(ctx).
+		QueryContext(ctx, _sql, birthday, pager.PageSize(), pager.Offset())
+	if _err != nil {
+		return nil, _err
+	}
+
+	defer _rows.Close()
+
+	var _items []*User
+	for _rows.Next() {
+		_item := &User{}
+		var _convTags string
+		var _jsonProfile []byte
+		_err = _rows.Scan(&_item.Id, &_item.Name, &_item.Gender, &_item.Birthday, &_item.Email, &_convTags, &_jsonProfile, &_item.CreatedAt)
+		if _err != nil {
+			return nil, _err
+		}
+		_item.Tags = ParseTags(_convTags)
+		if _err = dao.UnmarshalJSON(_jsonProfile, &_item.Profile); _err != nil {
+			return nil, _err
+		}
+		_items = append(_items, _item)
+	}
+	if _err = _rows.Err(); _err != nil {
+		return nil, _err
+	}
+
+	_page := &dao.Page[*User]{Items: _items, Page: pager.Page(), PageSize: pager.PageSize()}
+	_page.Total = int64(pager.Offset() + len(_items))
+	//count the rows when the page is full or out of range
+	if len(_items) == pager.PageSize() || (len(_items) == 0 && pager.Offset() > 0) {
+		_countSQL := "SELECT COUNT(*) FROM (SELECT id, name, gender, birthday, mail, tags, profile, created_at FROM \"user\" WHERE (\"birthday\" >= ?) ORDER BY \"name\" ASC) AS _page"
+		_err = _impl._router.Replica(ctx).
+			QueryRowContext(ctx, _countSQL, birthday).Scan(&_page.Total)
+		if _err != nil {
+			return nil, _err
+		}
+	}
+	return _page, nil
+}
+
+func (_impl *UserDaoSQLImpl) FindByBirthdayLT(ctx context.Context, birthday time.Time, fn func(*User) error) error {
+	_sql := "SELECT id, name, gender, birthday, mail, tags, profile, created_at FROM \"user\" WHERE (\"birthday\" < ?)"
+	_rows, _err := _impl._router.Replica(ctx).
+		QueryContext(ctx, _sql, birthday)
+	if _err != nil {
+		return _err
+	}
+
+	defer _rows.Close()
+
+	for _rows.Next() {
+		if _err = ctx.Err(); _err != nil {
+			return _err
+		}
+		_item := &User{}
+		var _convTags string
+		var _jsonProfile []byte
+		_err = _rows.Scan(&_item.Id, &_item.Name, &_item.Gender, &_item.Birthday, &_item.Email, &_convTags, &_jsonProfile, &_item.CreatedAt)
+		if _err != nil {
+			return _err
+		}
+		_item.Tags = ParseTags(_convTags)
+		if _err = dao.UnmarshalJSON(_jsonProfile, &_item.Profile); _err != nil {
+			return _err
+		}
+		if _err = fn(_item); _err != nil {
+			if _err == dao.Break {
+				return nil
+			}
+			return _err
+		}
+	}
+	return _rows.Err()
+}
+
+func (_impl *UserDaoSQLImpl) FindByEmail(ctx context.Context, email string) (*User, error) {
+	_sql := "SELECT id, name, gender, birthday, mail, tags, profile, created_at FROM \"user\" WHERE (\"mail\" = ?)"
+	_rows, _err := _impl._router.Replica(ctx).
+		QueryContext(ctx, _sql, email)
+
+	var _item *User
+	if _err != nil {
+		return _item, _err
+	}
+
+	defer _rows.Close()
+
+	if !_rows.Next() {
+		return _item, _rows.Err()
+	}
+
+	_item = &User{}
+	var _convTags string
+	var _jsonProfile []byte
+	_err = _rows.Scan(&_item.Id, &_item.Name, &_item.Gender, &_item.Birthday, &_item.Email, &_convTags, &_jsonProfile, &_item.CreatedAt)
+	if _err != nil {
+		return _item, _err
+	}
+	_item.Tags = ParseTags(_convTags)
+	if _err = dao.UnmarshalJSON(_jsonProfile, &_item.Profile); _err != nil {
+		return _item, _err
+	}
+	return _item, _err
+}
+
+func (_impl *UserDaoSQLImpl) FindByFilter(ctx context.Context, f *UserFilter) ([]*User, error) {
+	_builder := dao.NewSQLBuilder(dao.BindQuestion)
+	_builder.Write("select id, name, gender, birthday, mail, tags, profile, created_at from user ")
+	_builder.Begin(dao.ClauseWhere)
+	_builder.Write(" ")
+	if len(f.Name) > 0 {
+		_builder.Write(" and name like ")
+		_builder.Arg(f.Name)
+		_builder.Write(" ")
+	}
+	_builder.Write(" ")
+	if len(f.Genders) > 0 {
+		_builder.Write(" and gender in (")
+		dao.In(_builder, f.Genders)
+		_builder.Write(") ")
+	}
+	_builder.Write(" ")
+	if !f.Birthday.IsZero() {
+		_builder.Write(" and birthday >= ")
+		_builder.Arg(f.Birthday)
+		_builder.Write(" ")
+	}
+	_builder.Write(" ")
+	_builder.End()
+	_builder.Write(" order by id")
+	_sql, _args := _builder.SQL(), _builder.Args()
+	_rows, _err := _impl._router.Replica(ctx).
+		QueryContext(ctx, _sql, _args...)
+
+	var _items []*User
+	if _err != nil {
+		return _items, _err
+	}
+
+	defer _rows.Close()
+
+	for _rows.Next() {
+		_item := &User{}
+		var _convTags string
+		var _jsonProfile []byte
+		_err = _rows.Scan(&_item.Id, &_item.Name, &_item.Gender, &_item.Birthday, &_item.Email, &_convTags, &_jsonProfile, &_item.CreatedAt)
+		if _err != nil {
+			return _items, _err
+		}
+		_item.Tags = ParseTags(_convTags)
+		if _err = dao.UnmarshalJSON(_jsonProfile, &_item.Profile); _err != nil {
+			return _items, _err
+		}
+		_items = append(_items, _item)
+	}
+	return _items, _rows.Err()
+}
+
+func (_impl *UserDaoSQLImpl) FindByGender(ctx context.Context, gender Gender) (map[int64]*User, error) {
+	_sql := "SELECT id, name, gender, birthday, mail, tags, profile, created_at FROM \"user\" WHERE (\"gender\" = ?)"
+	_rows, _err := _impl._router.Replica(ctx).
+		QueryContext(ctx, _sql, gender)
+	if _err != nil {
+		return nil, _err
+	}
+
+	defer _rows.Close()
+
+	_items := map[int64]*User{}
+	for _rows.Next() {
+		_item := &User{}
+		var _convTags string
+		var _jsonProfile []byte
+		_err = _rows.Scan(&_item.Id, &_item.Name, &_item.Gender, &_item.Birthday, &_item.Email, &_convTags, &_jsonProfile, &_item.CreatedAt)
+		if _err != nil {
+			return _items, _err
+		}
+		_item.Tags = ParseTags(_convTags)
+		if _err = dao.UnmarshalJSON(_jsonProfile, &_item.Profile); _err != nil {
+			return _items, _err
+		}
+		_items[_item.Id] = _item
+	}
+	return _items, _rows.Err()
+}
+
+func (_impl *UserDaoSQLImpl) FindByGenderOrderByName(ctx context.Context, gender Gender, sorts []*query.Sort) ([]*User, error) {
+	_sql := "SELECT id, name, gender, birthday, mail, tags, profile, created_at FROM \"user\" WHERE (\"gender\" = ?) ORDER BY \"name\" ASC"
+	if len(sorts) > 0 {
+		_orderBy, _err := dao.OrderBy(sorts, map[string]string{
+			"Birthday":   "birthday",
+			"CreatedAt":  "created_at",
+			"Email":      "mail",
+			"Gender":     "\"gender\"",
+			"Id":         "id",
+			"Name":       "\"name\"",
+			"Profile":    "profile",
+			"Tags":       "tags",
+			"birthday":   "birthday",
+			"created_at": "created_at",
+			"gender":     "\"gender\"",
+			"id":         "id",
+			"mail":       "mail",
+			"name":       "\"name\"",
+			"profile":    "profile",
+			"tags":       "tags",
+		})
+		if _err != nil {
+			return nil, _err
+		}
+		_sql = "SELECT id, name, gender, birthday, mail, tags, profile, created_at FROM \"user\" WHERE (\"gender\" = ?) " + _orderBy
+	}
+	_rows, _err := _impl._router.Replica(ctx).
+		QueryContext(ctx, _sql, gender)
+
+	var _items []*User
+	if _err != nil {
+		return _items, _err
+	}
+
+	defer _rows.Close()
+
+	for _rows.Next() {
+		_item := &User{}
+		var _convTags string
+		var _jsonProfile []byte
+		_err = _rows.Scan(&_item.Id, &_item.Name, &_item.Gender, &_item.Birthday, &_item.Email, &_convTags, &_jsonProfile, &_item.CreatedAt)
+		if _err != nil {
+			return _items, _err
+		}
+		_item.Tags = ParseTags(_convTags)
+		if _err = dao.UnmarshalJSON(_jsonProfile, &_item.Profile); _err != nil {
+			return _items, _err
+		}
+		_items = append(_items, _item)
+	}
+	return _items, _rows.Err()
+}
+
+func (_impl *UserDaoSQLImpl) FindById(ctx context.Context, id int64) (*User, error) {
+	_sql := "SELECT id, name, gender, birthday, mail, tags, profile, created_at FROM \"user\" WHERE (\"id\" = ?)"
+	_rows, _err := _impl._router.Replica(ctx).
+		QueryContext(ctx, _sql, id)
+
+	var _item *User
+	if _err != nil {
+		return _item, _err
+	}
+
+	defer _rows.Close()
+
+	if !_rows.Next() {
+		return _item, _rows.Err()
+	}
+
+	_item = &User{}
+	var _convTags string
+	var _jsonProfile []byte
+	_err = _rows.Scan(&_item.Id, &_item.Name, &_item.Gender, &_item.Birthday, &_item.Email, &_convTags, &_jsonProfile, &_item.CreatedAt)
+	if _err != nil {
+		return _item, _err
+	}
+	_item.Tags = ParseTags(_convTags)
+	if _err = dao.UnmarshalJSON(_jsonProfile, &_item.Profile); _err != nil {
+		return _item, _err
+	}
+	return _item, _err
+}
+
+func (_impl *UserDaoSQLImpl) FindByIdIn(ctx context.Context, ids []int64) ([]*User, error) {
+	_builder := dao.NewSQLBuilder(dao.BindQuestion)
+	_builder.Write("SELECT id, name, gender, birthday, mail, tags, profile, created_at FROM \"user\" WHERE (\"id\" in (")
+	dao.In(_builder, ids)
+	_builder.Write("))")
+	_sql, _args := _builder.SQL(), _builder.Args()
+	_rows, _err := _impl._router.Replica(ctx).
+		QueryContext(ctx, _sql, _args...)
+
+	var _items []*User
+	if _err != nil {
+		return _items, _err
+	}
+
+	defer _rows.Close()
+
+	for _rows.Next() {
+		_item := &User{}
+		var _convTags string
+		var _jsonProfile []byte
+		_err = _rows.Scan(&_item.Id, &_item.Name, &_item.Gender, &_item.Birthday, &_item.Email, &_convTags, &_jsonProfile, &_item.CreatedAt)
+		if _err != nil {
+			return _items, _err
+		}
+		_item.Tags = ParseTags(_convTags)
+		if _err = dao.UnmarshalJSON(_jsonProfile, &_item.Profile); _err != nil {
+			return _items, _err
+		}
+		_items = append(_items, _item)
+	}
+	return _items, _rows.Err()
+}
+
+func (_impl *UserDaoSQLImpl) FindByKeyword(ctx context.Context, gender Gender, keyword string) ([]*User, error) {
+	_sql := "select id, name, gender, birthday, mail, tags, profile, created_at from user where (name = ? or mail = ?) and gender = ? order by id"
+	_rows, _err := _impl._router.Replica(ctx).
+		QueryContext(ctx, _sql, keyword, keyword, gender)
+
+	var _items []*User
+	if _err != nil {
+		return _items, _err
+	}
+
+	defer _rows.Close()
+
+	for _rows.Next() {
+		_item := &User{}
+		var _convTags string
+		var _jsonProfile []byte
+		_err = _rows.Scan(&_item.Id, &_item.Name, &_item.Gender, &_item.Birthday, &_item.Email, &_convTags, &_jsonProfile, &_item.CreatedAt)
+		if _err != nil {
+			return _items, _err
+		}
+		_item.Tags = ParseTags(_convTags)
+		if _err = dao.UnmarshalJSON(_jsonProfile, &_item.Profile); _err != nil {
+			return _items, _err
+		}
+		_items = append(_items, _item)
+	}
+	return _items, _rows.Err()
+}
+
+func (_impl *UserDaoSQLImpl) FindByMail2(ctx context.Context, email string) (*User, error) {
+	_sql := "select id, name, mail from user where mail = ?"
+	_rows, _err := _impl._router.Replica(ctx).
+		QueryContext(ctx, _sql, email)
+
+	var _item *User
+	if _err != nil {
+		return _item, _err
+	}
+
+	defer _rows.Close()
+
+	if !_rows.Next() {
+		return _item, _rows.Err()
+	}
+
+	_item = &User{}
+	_err = _rows.Scan(&_item.Id, &_item.Name, &_item.Email)
+	return _item, _err
+}
+
+func (_impl *UserDaoSQLImpl) FindByNameContains(ctx context.Context, name string) ([]*User, error) {
+	_sql := "SELECT id, name, gender, birthday, mail, tags, profile, created_at FROM \"user\" WHERE (\"name\" LIKE '%' || ? || '%')"
+	_rows, _err := _impl._router.Replica(ctx).
+		QueryContext(ctx, _sql, name)
+
+	var _items []*User
+	if _err != nil {
+		return _items, _err
+	}
+
+	defer _rows.Close()
+
+	for _rows.Next() {
+		_item := &User{}
+		var _convTags string
+		var _jsonProfile []byte
+		_err = _rows.Scan(&_item.Id, &_item.Name, &_item.Gender, &_item.Birthday, &_item.Email, &_convTags, &_jsonProfile, &_item.CreatedAt)
+		if _err != nil {
+			return _items, _err
+		}
+		_item.Tags = ParseTags(_convTags)
+		if _err = dao.UnmarshalJSON(_jsonProfile, &_item.Profile); _err != nil {
+			return _items, _err
+		}
+		_items = append(_items, _item)
+	}
+	return _items, _rows.Err()
+}
+
+func (_impl *UserDaoSQLImpl) FindDetailById(ctx context.Context, id int64) (*UserDetail, error) {
+	_sql := "select u.id, u.name, u.created_at, a.phone as addr__phone, a.city as addr__city from user u inner join address a on a.user_id = u.id where u.id = ?"
+	_rows, _err := _impl._router.Replica(ctx).
+		QueryContext(ctx, _sql, id)
+
+	var _item *UserDetail
+	if _err != nil {
+		return _item, _err
+	}
+
+	defer _rows.Close()
+
+	if !_rows.Next() {
+		return _item, _rows.Err()
+	}
+
+	_item = &UserDetail{}
+	var _nullAddressCity sql.NullString
+	_err = _rows.Scan(&_item.Id, &_item.Name, &_item.CreatedAt, &_item.Address.Phone, &_nullAddressCity)
+	if _err != nil {
+		return _item, _err
+	}
+	_item.Address.City = _nullAddressCity.String
+	return _item, _err
+}
+
+func (_impl *UserDaoSQLImpl) FindDetails(ctx context.Context) ([]*UserDetail, error) {
+	_sql := "select u.name, u.id, u.created_at, a.city addr__city from user u inner join address a on a.user_id = u.id"
+	_rows, _err := _impl._router.Replica(ctx).
+		QueryContext(ctx, _sql)
+
+	var _items []*UserDetail
+	if _err != nil {
+		return _items, _err
+	}
+
+	defer _rows.Close()
+
+	for _rows.Next() {
+		_item := &UserDetail{}
+		var _nullAddressCity sql.NullString
+		_err = _rows.Scan(&_item.Name, &_item.Id, &_item.CreatedAt, &_nullAddressCity)
+		if _err != nil {
+			return _items, _err
+		}
+		_item.Address.City = _nullAddressCity.String
+		_items = append(_items, _item)
+	}
+	return _items, _rows.Err()
+}
+
+func (_impl *UserDaoSQLImpl) FindDetailsAfter(ctx context.Context, birthday time.Time, cursor *dao.CursorRequest) (*dao.CursorPage[*UserDetail], error) {
+	if cursor.Limit <= 0 {
+		return nil, dao.ErrInvalidLimit
+	}
+	_sql := "select u.id, u.name, a.city as addr__city from user u left join address a on a.user_id = u.id where u.birthday >= ? ORDER BY u.id LIMIT ?"
+	_args := []any{birthday, cursor.Limit + 1}
+	if len(cursor.After) > 0 {
+		var _afterId int64
+		if _err := dao.DecodeCursor(cursor.After, &_afterId); _err != nil {
+			return nil, _err
+		}
+		_sql = "select u.id, u.name, a.city as addr__city from user u left join address a on a.user_id = u.id WHERE (u.birthday >= ?) AND u.id > ? ORDER BY u.id LIMIT ?"
+		_args = []any{birthday, _afterId, cursor.Limit + 1}
+	}
+	_rows, _err := _impl._router.Replica(ctx).
+		QueryContext(ctx, _sql, _args...)
+	if _err != nil {
+		return nil, _err
+	}
+
+	defer _rows.Close()
+
+	var _items []*UserDetail
+	for _rows.Next() {
+		_item := &UserDetail{}
+		var _nullAddressCity sql.NullString
+		_err = _rows.Scan(&_item.Id, &_item.Name, &_nullAddressCity)
+		if _err != nil {
+			return nil, _err
+		}
+		_item.Address.City = _nullAddressCity.String
+		_items = append(_items, _item)
+	}
+	if _err = _rows.Err(); _err != nil {
+		return nil, _err
+	}
+	return dao.NewCursorPage(_items, cursor.Limit, func(_item *UserDetail) []any {
+		return []any{_item.Id}
+	})
+}
+
+func (_impl *UserDaoSQLImpl) FindDetailsByGender(ctx context.Context, gender Gender, fn func(UserDetail) error) error {
+	_sql := "select u.id, u.name, a.city as addr__city from user u left join address a on a.user_id = u.id where u.gender = ? order by u.id"
+	_rows, _err := _impl._router.Replica(ctx).
+		QueryContext(ctx, _sql, gender)
+	if _err != nil {
+		return _err
+	}
+
+	defer _rows.Close()
+
+	for _rows.Next() {
+		if _err = ctx.Err(); _err != nil {
+			return _err
+		}
+		var _item UserDetail
+		var _nullAddressCity sql.NullString
+		_err = _rows.Scan(&_item.Id, &_item.Name, &_nullAddressCity)
+		if _err != nil {
+			return _err
+		}
+		_item.Address.City = _nullAddressCity.String
+		if _err = fn(_item); _err != nil {
+			if _err == dao.Break {
+				return nil
+			}
+			return _err
+		}
+	}
+	return _rows.Err()
+}
+
+func (_impl *UserDaoSQLImpl) FindDetailsByName(ctx context.Context, name string) (map[string]UserDetail, error) {
+	_sql := "select u.id, u.name, a.phone as addr__phone from user u left join address a on a.user_id = u.id where u.name = ?"
+	_rows, _err := _impl._router.Replica(ctx).
+		QueryContext(ctx, _sql, name)
+	if _err != nil {
+		return nil, _err
+	}
+
+	defer _rows.Close()
+
+	_items := map[string]UserDetail{}
+	for _rows.Next() {
+		_item := UserDetail{}
+		var _nullId sql.NullInt64
+		var _nullName sql.NullString
+		var _nullAddressPhone sql.NullString
+		_err = _rows.Scan(&_nullId, &_nullName, &_nullAddressPhone)
+		if _err != nil {
+			return _items, _err
+		}
+		_item.Id = _nullId.Int64
+		_item.Name = _nullName.String
+		_item.Address.Phone = _nullAddressPhone.String
+		_items[_item.Address.Phone] = _item
+	}
+	return _items, _rows.Err()
+}
+
+func (_impl *UserDaoSQLImpl) FindDetailsSorted(ctx context.Context, sorts []*query.Sort) ([]*UserDetail, error) {
+	_sql := "select u.id, u.name, a.city as addr__city from user u left join address a on a.user_id = u.id"
+	if len(sorts) > 0 {
+		_orderBy, _err := dao.OrderBy(sorts, map[string]string{
+			"Address.City": "a.city",
+			"Id":           "u.id",
+			"Name":         "u.name",
+			"addr__city":   "a.city",
+			"id":           "u.id",
+			"name":         "u.name",
+		})
+		if _err != nil {
+			return nil, _err
+		}
+		_sql = "select u.id, u.name, a.city as addr__city from user u left join address a on a.user_id = u.id " + _orderBy
+	}
+	_rows, _err := _impl._router.Replica(ctx).
+		QueryContext(ctx, _sql)
+
+	var _items []*UserDetail
+	if _err != nil {
+		return _items, _err
+	}
+
+	defer _rows.Close()
+
+	for _rows.Next() {
+		_item := &UserDetail{}
+		var _nullAddressCity sql.NullString
+		_err = _rows.Scan(&_item.Id, &_item.Name, &_nullAddressCity)
+		if _err != nil {
+			return _items, _err
+		}
+		_item.Address.City = _nullAddressCity.String
+		_items = append(_items, _item)
+	}
+	return _items, _rows.Err()
+}
+
+func (_impl *UserDaoSQLImpl) FindDetailsWithAddress(ctx context.Context) ([]*UserDetail, error) {
+	_sql := "select u.id, u.name, u.created_at, a.phone as addr__phone, a.city as addr__city from user u left join address a on a.user_id = u.id order by u.id"
+	_rows, _err := _impl._router.Replica(ctx).
+		QueryContext(ctx, _sql)
+
+	var _items []*UserDetail
+	if _err != nil {
+		return _items, _err
+	}
+
+	defer _rows.Close()
+
+	for _rows.Next() {
+		_item := &UserDetail{}
+		var _nullId sql.NullInt64
+		var _nullName sql.NullString
+		var _nullCreatedAt sql.NullTime
+		var _nullAddressPhone sql.NullString
+		var _nullAddressCity sql.NullString
+		_err = _rows.Scan(&_nullId, &_nullName, &_nullCreatedAt, &_nullAddressPhone, &_nullAddressCity)
+		if _err != nil {
+			return _items, _err
+		}
+		_item.Id = _nullId.Int64
+		_item.Name = _nullName.String
+		_item.CreatedAt = _nullCreatedAt.Time
+		_item.Address.Phone = _nullAddressPhone.String
+		_item.Address.City = _nullAddressCity.String
+		_items = append(_items, _item)
+	}
+	return _items, _rows.Err()
+}
+
+func (_impl *UserDaoSQLImpl) FindDetailsWithCity(ctx context.Context) ([]*UserDetail, error) {
+	_sql := "select u.id, u.name, a.city as addr__city from user u left join address a on a.user_id = u.id order by u.id"
+	_rows, _err := _impl._router.Replica(ctx).
+		QueryContext(ctx, _sql)
+
+	var _items []*UserDetail
+	if _err != nil {
+		return _items, _err
+	}
+
+	defer _rows.Close()
+
+	for _rows.Next() {
+		_item := &UserDetail{}
+		var _nullAddressCity sql.NullString
+		_err = _rows.Scan(&_item.Id, &_item.Name, &_nullAddressCity)
+		if _err != nil {
+			return _items, _err
+		}
+		_item.Address.City = _nullAddressCity.String
+		_items = append(_items, _item)
+	}
+	return _items, _rows.Err()
+}
+
+func (_impl *UserDaoSQLImpl) FindIdsAfter(ctx context.Context, cursor dao.CursorRequest) (*dao.CursorPage[int64], error) {
+	if cursor.Limit <= 0 {
+		return nil, dao.ErrInvalidLimit
+	}
+	_sql := "select id from user ORDER BY id LIMIT ?"
+	_args := []any{cursor.Limit + 1}
+	if len(cursor.After) > 0 {
+		var _after int64
+		if _err := dao.DecodeCursor(cursor.After, &_after); _err != nil {
+			return nil, _err
+		}
+		_sql = "select id from user WHERE id > ? ORDER BY id LIMIT ?"
+		_args = []any{_after, cursor.Limit + 1}
+	}
+	_rows, _err := _impl._router.Replica(ctx).
+		QueryContext(ctx, _sql, _args...)
+	if _err != nil {
+		return nil, _err
+	}
+
+	defer _rows.Close()
+
+	var _items []int64
+	for _rows.Next() {
+		var _item int64
+		_err = _rows.Scan(&_item)
+		if _err != nil {
+			return nil, _err
+		}
+		_items = append(_items, _item)
+	}
+	if _err = _rows.Err(); _err != nil {
+		return nil, _err
+	}
+	return dao.NewCursorPage(_items, cursor.Limit, func(_item int64) []any {
+		return []any{_item}
+	})
+}
+
+func (_impl *UserDaoSQLImpl) FindMaxGenderByName(ctx context.Context, name string) (Gender, error) {
+	_sql := "select max(gender) as gender from user where name = ?"
+	_rows, _err := _impl._router.Replica(ctx).
+		QueryContext(ctx, _sql, name)
+
+	var _item Gender
+	if _err != nil {
+		return _item, _err
+	}
+
+	defer _rows.Close()
+
+	if !_rows.Next() {
+		return _item, _rows.Err()
+	}
+
+	var _nullItem sql.NullByte
+	_err = _rows.Scan(&_nullItem)
+	if _err != nil {
+		return _item, _err
+	}
+	_item = Gender(_nullItem.Byte)
+	return _item, _err
+}
+
+func (_impl *UserDaoSQLImpl) FindNames(ctx context.Context) (*dao.Rows[string], error) {
+	_sql := "select name from user order by id"
+	_rows, _err := _impl._router.Replica(ctx).
+		QueryContext(ctx, _sql)
+	if _err != nil {
+		return nil, _err
+	}
+	return dao.NewRows(ctx, _rows, func(_rows *sql.Rows) (string, error) {
+		var _err error
+		var _item string
+		_err = _rows.Scan(&_item)
+		if _err != nil {
+			return _item, _err
+		}
+		return _item, nil
+	}), nil
+}
+
+func (_impl *UserDaoSQLImpl) FindNamesAfter(ctx context.Context, cursor dao.CursorRequest) (*dao.CursorPage[*User], error) {
+	if cursor.Limit <= 0 {
+		return nil, dao.ErrInvalidLimit
+	}
+	_sql := "select id, name from user ORDER BY name, id LIMIT ?"
+	_args := []any{cursor.Limit + 1}
+	if len(cursor.After) > 0 {
+		var _afterName string
+		var _afterId int64
+		if _err := dao.DecodeCursor(cursor.After, &_afterName, &_afterId); _err != nil {
+			return nil, _err
+		}
+		_sql = "select id, name from user WHERE (name, id) > (?, ?) ORDER BY name, id LIMIT ?"
+		_args = []any{_afterName, _afterId, cursor.Limit + 1}
+	}
+	_rows, _err := _impl._router.Replica(ctx).
+		QueryContext(ctx, _sql, _args...)
+	if _err != nil {
+		return nil, _err
+	}
+
+	defer _rows.Close()
+
+	var _items []*User
+	for _rows.Next() {
+		_item := &User{}
+		_err = _rows.Scan(&_item.Id, &_item.Name)
+		if _err != nil {
+			return nil, _err
+		}
+		_items = append(_items, _item)
+	}
+	if _err = _rows.Err(); _err != nil {
+		return nil, _err
+	}
+	return dao.NewCursorPage(_items, cursor.Limit, func(_item *User) []any {
+		return []any{_item.Name, _item.Id}
+	})
+}
+
+func (_impl *UserDaoSQLImpl) FindNamesByGender(ctx context.Context, gender Gender, pager query.Pager) (*dao.Slice[string], error) {
+	_sql := "select name from user where gender = ? order by id LIMIT ? OFFSET ?"
+	_rows, _err := _impl._router.Replica(ctx).
+		QueryContext(ctx, _sql, gender, pager.PageSize()+1, pager.Offset())
+	if _err != nil {
+		return nil, _err
+	}
+
+	defer _rows.Close()
+
+	var _items []string
+	for _rows.Next() {
+		var _item string
+		_err = _rows.Scan(&_item)
+		if _err != nil {
+			return nil, _err
+		}
+		_items = append(_items, _item)
+	}
+	if _err = _rows.Err(); _err != nil {
+		return nil, _err
+	}
+
+	_slice := &dao.Slice[string]{Items: _items, Page: pager.Page(), PageSize: pager.PageSize()}
+	if len(_items) > pager.PageSize() {
+		_slice.Items, _slice.HasNext = _items[:pager.PageSize()], true
+	}
+	return _slice, nil
+}
+
+func (_impl *UserDaoSQLImpl) FindNamesByGenders(ctx context.Context, genders []Gender, name string) ([]string, error) {
+	_builder := dao.NewSQLBuilder(dao.BindQuestion)
+	_builder.Write("select name from user where gender in (")
+	dao.In(_builder, genders)
+	_builder.Write(") and name <> ")
+	_builder.Arg(name)
+	_builder.Write(" order by id")
+	_sql, _args := _builder.SQL(), _builder.Args()
+	_rows, _err := _impl._router.Replica(ctx).
+		QueryContext(ctx, _sql, _args...)
+
+	var _items []string
+	if _err != nil {
+		return _items, _err
+	}
+
+	defer _rows.Close()
+
+	for _rows.Next() {
+		_item := ""
+		_err = _rows.Scan(&_item)
+		if _err != nil {
+			return _items, _err
+		}
+		_items = append(_items, _item)
+	}
+	return _items, _rows.Err()
+}
+
+func (_impl *UserDaoSQLImpl) FindNamesByIdNotIn(ctx context.Context, ids []int64) ([]string, error) {
+	_builder := dao.NewSQLBuilder(dao.BindQuestion)
+	_builder.Write("select name from user where ")
+	dao.NotIn(_builder, "id", ids)
+	_builder.Write(" order by id")
+	_sql, _args := _builder.SQL(), _builder.Args()
+	_rows, _err := _impl._router.Replica(ctx).
+		QueryContext(ctx, _sql, _args...)
+
+	var _items []string
+	if _err != nil {
+		return _items, _err
+	}
+
+	defer _rows.Close()
+
+	for _rows.Next() {
+		_item := ""
+		_err = _rows.Scan(&_item)
+		if _err != nil {
+			return _items, _err
+		}
+		_items = append(_items, _item)
+	}
+	return _items, _rows.Err()
+}
+
+func (_impl *UserDaoSQLImpl) FindRowById(ctx context.Context, id int64) (map[string]any, error) {
+	_sql := "select id, name, gender from user where id = ?"
+	_rows, _err := _impl._router.Replica(ctx).
+		QueryContext(ctx, _sql, id)
+	if _err != nil {
+		return nil, _err
+	}
+
+	defer _rows.Close()
+
+	if !_rows.Next() {
+		return nil, _rows.Err()
+	}
+	return dao.ScanMap(_rows)
+}
+
+func (_impl *UserDaoSQLImpl) Insert(ctx context.Context, user *User) (*User, error) {
+	_builder := dao.NewSQLBuilder(dao.BindQuestion)
+	_builder.Write("INSERT INTO \"user\" (\"name\", \"gender\", \"birthday\", \"mail\", \"tags\", \"profile\"")
+	if !user.CreatedAt.IsZero() {
+		_builder.Write(", \"created_at\"")
+	}
+	_builder.Write(") VALUES (")
+	_builder.Arg(user.Name)
+	_builder.Write(", ")
+	_builder.Arg(user.Gender)
+	_builder.Write(", ")
+	_builder.Arg(user.Birthday)
+	_builder.Write(", ")
+	_builder.Arg(user.Email)
+	_builder.Write(", ")
+	_builder.Arg(dao.ValueFunc(user.Tags, FormatTags))
+	_builder.Write(", ")
+	_builder.Arg(dao.JSONValue(user.Profile))
+	if !user.CreatedAt.IsZero() {
+		_builder.Write(", ")
+		_builder.Arg(user.CreatedAt)
+	}
+	_builder.Write(")")
+	_sql, _args := _builder.SQL(), _builder.Args()
+	_result, _err := _impl._router.Primary(ctx).
+		ExecContext(ctx, _sql, _args...)
+	if _err != nil {
+		return nil, _err
+	}
+	_id, _err := _result.LastInsertId()
+	if _err != nil {
+		return nil, _err
+	}
+	user.Id = _id
+	return user, nil
+}
+
+func (_impl *UserDaoSQLImpl) InsertBatch(ctx context.Context, users []*User) ([]*User, error) {
+	_db := _impl._router.Primary(ctx)
+	var _rowsAffected int64
+	for _start := 0; _start < len(users); _start += 142 {
+		_end := _start + 142
+		if _end > len(users) {
+			_end = len(users)
+		}
+		_sqlBuilder := strings.Builder{}
+		_sqlBuilder.WriteString("INSERT INTO \"user\" (\"name\", \"gender\", \"birthday\", \"mail\", \"tags\", \"profile\", \"created_at\") VALUES ")
+		_args := make([]any, 0, (_end-_start)*7)
+		for _i, _item := range users[_start:_end] {
+			if _i > 0 {
+				_sqlBuilder.WriteString(", ")
+			}
+			_sqlBuilder.WriteString("(?, ?, ?, ?, ?, ?, ?)")
+			_args = append(_args, _item.Name, _item.Gender, _item.Birthday, _item.Email, dao.ValueFunc(_item.Tags, FormatTags), dao.JSONValue(_item.Profile), _item.CreatedAt)
+		}
+		_result, _err := _db.ExecContext(ctx, _sqlBuilder.String(), _args...)
+		if _err != nil {
+			return nil, _err
+		}
+		_affected, _err := _result.RowsAffected()
+		if _err != nil {
+			return nil, _err
+		}
+		_rowsAffected += _affected
+		_id, _err := _result.LastInsertId()
+		if _err != nil {
+			return nil, _err
+		}
+		_id -= int64(_end - _start - 1)
+		for _i := _start; _i < _end; _i++ {
+			users[_i].Id = _id + int64(_i-_start)
+		}
+	}
+	return users, nil
+}
+
+func (_impl *UserDaoSQLImpl) InsertCount(ctx context.Context, user *User) (validId int64, err error) {
+	_builder := dao.NewSQLBuilder(dao.BindQuestion)
+	_builder.Write("INSERT INTO \"user\" (\"name\", \"gender\", \"birthday\", \"mail\", \"tags\", \"profile\"")
+	if !user.CreatedAt.IsZero() {
+		_builder.Write(", \"created_at\"")
+	}
+	_builder.Write(") VALUES (")
+	_builder.Arg(user.Name)
+	_builder.Write(", ")
+	_builder.Arg(user.Gender)
+	_builder.Write(", ")
+	_builder.Arg(user.Birthday)
+	_builder.Write(", ")
+	_builder.Arg(user.Email)
+	_builder.Write(", ")
+	_builder.Arg(dao.ValueFunc(user.Tags, FormatTags))
+	_builder.Write(", ")
+	_builder.Arg(dao.JSONValue(user.Profile))
+	if !user.CreatedAt.IsZero() {
+		_builder.Write(", ")
+		_builder.Arg(user.CreatedAt)
+	}
+	_builder.Write(")")
+	_sql, _args := _builder.SQL(), _builder.Args()
+	_result, _err := _impl._router.Primary(ctx).
+		ExecContext(ctx, _sql, _args...)
+	if _err != nil {
+		return 0, _err
+	}
+	return _result.RowsAffected()
+}
+
+func (_impl *UserDaoSQLImpl) InsertForId(ctx context.Context, user *User) (int64, error) {
+	_builder := dao.NewSQLBuilder(dao.BindQuestion)
+	_builder.Write("INSERT INTO \"user\" (\"name\", \"gender\", \"birthday\", \"mail\", \"tags\", \"profile\"")
+	if !user.CreatedAt.IsZero() {
+		_builder.Write(", \"created_at\"")
+	}
+	_builder.Write(") VALUES (")
+	_builder.Arg(user.Name)
+	_builder.Write(", ")
+	_builder.Arg(user.Gender)
+	_builder.Write(", ")
+	_builder.Arg(user.Birthday)
+	_builder.Write(", ")
+	_builder.Arg(user.Email)
+	_builder.Write(", ")
+	_builder.Arg(dao.ValueFunc(user.Tags, FormatTags))
+	_builder.Write(", ")
+	_builder.Arg(dao.JSONValue(user.Profile))
+	if !user.CreatedAt.IsZero() {
+		_builder.Write(", ")
+		_builder.Arg(user.CreatedAt)
+	}
+	_builder.Write(")")
+	_sql, _args := _builder.SQL(), _builder.Args()
+	_result, _err := _impl._router.Primary(ctx).
+		ExecContext(ctx, _sql, _args...)
+	if _err != nil {
+		return 0, _err
+	}
+	return _result.LastInsertId()
+}
+
+func (_impl *UserDaoSQLImpl) QueryByGender(ctx context.Context, gender Gender, cursor dao.CursorRequest) (*dao.CursorPage[*User], error) {
+	if cursor.Limit <= 0 {
+		return nil, dao.ErrInvalidLimit
+	}
+	_sql := "SELECT id, name, gender, birthday, mail, tags, profile, created_at FROM \"user\" WHERE (\"gender\" = ?) ORDER BY birthday DESC, id DESC LIMIT ?"
+	_args := []any{gender, cursor.Limit + 1}
+	if len(cursor.After) > 0 {
+		var _afterBirthday time.Time
+		var _afterId int64
+		if _err := dao.DecodeCursor(cursor.After, &_afterBirthday, &_afterId); _err != nil {
+			return nil, _err
+		}
+		_sql = "SELECT id, name, gender, birthday, mail, tags, profile, created_at FROM \"user\" WHERE (\"gender\" = ?) AND (birthday, id) < (?, ?) ORDER BY birthday DESC, id DESC LIMIT ?"
+		_args = []any{gender, _afterBirthday, _afterId, cursor.Limit + 1}
+	}
+	_rows, _err := _impl._router.Replica(ctx).
+		QueryContext(ctx, _sql, _args...)
+	if _err != nil {
+		return nil, _err
+	}
+
+	defer _rows.Close()
+
+	var _items []*User
+	for _rows.Next() {
+		_item := &User{}
+		var _convTags string
+		var _jsonProfile []byte
+		_err = _rows.Scan(&_item.Id, &_item.Name, &_item.Gender, &_item.Birthday, &_item.Email, &_convTags, &_jsonProfile, &_item.CreatedAt)
+		if _err != nil {
+			return nil, _err
+		}
+		_item.Tags = ParseTags(_convTags)
+		if _err = dao.UnmarshalJSON(_jsonProfile, &_item.Profile); _err != nil {
+			return nil, _err
+		}
+		_items = append(_items, _item)
+	}
+	if _err = _rows.Err(); _err != nil {
+		return nil, _err
+	}
+	return dao.NewCursorPage(_items, cursor.Limit, func(_item *User) []any {
+		return []any{_item.Birthday, _item.Id}
+	})
+}
+
+func (_impl *UserDaoSQLImpl) QueryByGenderOrderById(ctx context.Context, gender Gender) (*dao.Rows[*User], error) {
+	_sql := "SELECT id, name, gender, birthday, mail, tags, profile, created_at FROM \"user\" WHERE (\"gender\" = ?) ORDER BY \"id\" ASC"
+	_rows, _err := _impl._router.Replica(ctx).
+		QueryContext(ctx, _sql, gender)
+	if _err != nil {
+		return nil, _err
+	}
+	return dao.NewRows(ctx, _rows, func(_rows *sql.Rows) (*User, error) {
+		var _err error
+		_item := &User{}
+		var _convTags string
+		var _jsonProfile []byte
+		_err = _rows.Scan(&_item.Id, &_item.Name, &_item.Gender, &_item.Birthday, &_item.Email, &_convTags, &_jsonProfile, &_item.CreatedAt)
+		if _err != nil {
+			return _item, _err
+		}
+		_item.Tags = ParseTags(_convTags)
+		if _err = dao.UnmarshalJSON(_jsonProfile, &_item.Profile); _err != nil {
+			return _item, _err
+		}
+		return _item, nil
+	}), nil
+}
+
+func (_impl *UserDaoSQLImpl) SaveBatch(ctx context.Context, users []*User) ([]*User, error) {
+	_db := _impl._router.Primary(ctx)
+	var _rowsAffected int64
+	for _start := 0; _start < len(users); _start += 142 {
+		_end := _start + 142
+		if _end > len(users) {
+			_end = len(users)
+		}
+		_sqlBuilder := strings.Builder{}
+		_sqlBuilder.WriteString("INSERT INTO \"user\" (\"name\", \"gender\", \"birthday\", \"mail\", \"tags\", \"profile\", \"created_at\") VALUES ")
+		_args := make([]any, 0, (_end-_start)*7)
+		for _i, _item := range users[_start:_end] {
+			if _i > 0 {
+				_sqlBuilder.WriteString(", ")
+			}
+			_sqlBuilder.WriteString("(?, ?, ?, ?, ?, ?, ?)")
+			_args = append(_args, _item.Name, _item.Gender, _item.Birthday, _item.Email, dao.ValueFunc(_item.Tags, FormatTags), dao.JSONValue(_item.Profile), _item.CreatedAt)
+		}
+		_result, _err := _db.ExecContext(ctx, _sqlBuilder.String(), _args...)
+		if _err != nil {
+			return nil, _err
+		}
+		_affected, _err := _result.RowsAffected()
+		if _err != nil {
+			return nil, _err
+		}
+		_rowsAffected += _affected
+	}
+	return users, nil
+}
+
+func (_impl *UserDaoSQLImpl) Search(ctx context.Context, name string, gender Gender, birthday time.Time) ([]*User, error) {
+	_builder := dao.NewSQLBuilder(dao.BindQuestion)
+	_builder.Write("select id, name, gender, birthday, mail, tags, profile, created_at from user ")
+	_builder.Begin(dao.ClauseWhere)
+	_builder.Write(" ")
+	if len(name) > 0 {
+		_builder.Write(" and name like ")
+		_builder.Arg(name)
+		_builder.Write(" ")
+	}
+	_builder.Write(" ")
+	if gender != 0 {
+		_builder.Write(" and gender = ")
+		_builder.Arg(gender)
+		_builder.Write(" ")
+	}
+	_builder.Write(" ")
+	if !birthday.IsZero() {
+		_builder.Write(" and birthday >= ")
+		_builder.Arg(birthday)
+		_builder.Write(" ")
+	}
+	_builder.Write(" ")
+	_builder.End()
+	_builder.Write(" order by id")
+	_sql, _args := _builder.SQL(), _builder.Args()
+	_rows, _err := _impl._router.Replica(ctx).
+		QueryContext(ctx, _sql, _args...)
+
+	var _items []*User
+	if _err != nil {
+		return _items, _err
+	}
+
+	defer _rows.Close()
+
+	for _rows.Next() {
+		_item := &User{}
+		var _convTags string
+		var _jsonProfile []byte
+		_err = _rows.Scan(&_item.Id, &_item.Name, &_item.Gender, &_item.Birthday, &_item.Email, &_convTags, &_jsonProfile, &_item.CreatedAt)
+		if _err != nil {
+			return _items, _err
+		}
+		_item.Tags = ParseTags(_convTags)
+		if _err = dao.UnmarshalJSON(_jsonProfile, &_item.Profile); _err != nil {
+			return _items, _err
+		}
+		_items = append(_items, _item)
+	}
+	return _items, _rows.Err()
+}
+
+func (_impl *UserDaoSQLImpl) UpdateById(ctx context.Context, id int64, user *User) (int64, error) {
+	_builder := dao.NewSQLBuilder(dao.BindQuestion)
+	_builder.Write("UPDATE \"user\" ")
+	_builder.Begin(dao.ClauseSet)
+	_builder.Write(" \"name\" = ")
+	_builder.Arg(user.Name)
+	_builder.Write(", \"gender\" = ")
+	_builder.Arg(user.Gender)
+	_builder.Write(", \"birthday\" = ")
+	_builder.Arg(user.Birthday)
+	_builder.Write(", \"mail\" = ")
+	_builder.Arg(user.Email)
+	_builder.Write(", \"tags\" = ")
+	_builder.Arg(dao.ValueFunc(user.Tags, FormatTags))
+	_builder.Write(", \"profile\" = ")
+	_builder.Arg(dao.JSONValue(user.Profile))
+	_builder.Write(", ")
+	if !user.CreatedAt.IsZero() {
+		_builder.Write(" \"created_at\" = ")
+		_builder.Arg(user.CreatedAt)
+		_builder.Write(", ")
+	}
+	_builder.Write(" ")
+	_builder.End()
+	_builder.Write(" WHERE (\"id\" = ")
+	_builder.Arg(id)
+	_builder.Write(")")
+	_sql, _args := _builder.SQL(), _builder.Args()
+	_result, _err := _impl._router.Primary(ctx).
+		ExecContext(ctx, _sql, _args...)
+	if _err != nil {
+		return 0, _err
+	}
+	return _result.RowsAffected()
+}
+
+func (_impl *UserDaoSQLImpl) UpdateEmailById(ctx context.Context, email string, id int64) (int64, error) {
+	_sql := "UPDATE \"user\" SET \"mail\" = ? WHERE (\"id\" = ?)"
+	_result, _err := _impl._router.Primary(ctx).
+		ExecContext(ctx, _sql, email, id)
+	if _err != nil {
+		return 0, _err
+	}
+	return _result.RowsAffected()
+}
+
+func (_impl *UserDaoSQLImpl) UpdateNameAndGenderById(ctx context.Context, name string, gender Gender, id int64) (int64, error) {
+	_sql := "UPDATE \"user\" SET \"name\" = ?, \"gender\" = ? WHERE (\"id\" = ?)"
+	_result, _err := _impl._router.Primary(ctx).
+		ExecContext(ctx, _sql, name, gender, id)
+	if _err != nil {
+		return 0, _err
+	}
+	return _result.RowsAffected()
+}
+
+func (_impl *UserDaoSQLImpl) UpdateSelective(ctx context.Context, id int64, user *User) (int64, error) {
+	_builder := dao.NewSQLBuilder(dao.BindQuestion)
+	_builder.Write("update user ")
+	_builder.Begin(dao.ClauseSet)
+	_builder.Write(" ")
+	if len(user.Name) > 0 {
+		_builder.Write(" name = ")
+		_builder.Arg(user.Name)
+		_builder.Write(", ")
+	}
+	_builder.Write(" ")
+	if len(user.Email) > 0 {
+		_builder.Write(" mail = ")
+		_builder.Arg(user.Email)
+		_builder.Write(", ")
+	}
+	_builder.Write(" ")
+	_builder.End()
+	_builder.Write(" where id = ")
+	_builder.Arg(id)
+	_sql, _args := _builder.SQL(), _builder.Args()
+	_result, _err := _impl._router.Primary(ctx).
+		ExecContext(ctx, _sql, _args...)
+	if _err != nil {
+		return 0, _err
+	}
+	return _result.RowsAffected()
+}
+
+func (_impl *UserDaoSQLImpl) Upsert(ctx context.Context, user *User) (*User, error) {
+	_builder := dao.NewSQLBuilder(dao.BindQuestion)
+	_builder.Write("INSERT INTO \"user\" (")
+	if user.Id != 0 {
+		_builder.Write("\"id\", ")
+	}
+	_builder.Write("\"name\", \"gender\", \"birthday\", \"mail\", \"tags\", \"profile\", \"created_at\") VALUES (")
+	if user.Id != 0 {
+		_builder.Arg(user.Id)
+		_builder.Write(", ")
+	}
+	_builder.Arg(user.Name)
+	_builder.Write(", ")
+	_builder.Arg(user.Gender)
+	_builder.Write(", ")
+	_builder.Arg(user.Birthday)
+	_builder.Write(", ")
+	_builder.Arg(user.Email)
+	_builder.Write(", ")
+	_builder.Arg(dao.ValueFunc(user.Tags, FormatTags))
+	_builder.Write(", ")
+	_builder.Arg(dao.JSONValue(user.Profile))
+	_builder.Write(", ")
+	_builder.Arg(user.CreatedAt)
+	_builder.Write(") ON CONFLICT (\"id\") DO UPDATE SET \"name\" = EXCLUDED.\"name\", \"gender\" = EXCLUDED.\"gender\" RETURNING \"id\"")
+	_sql, _args := _builder.SQL(), _builder.Args()
+	var _id int64
+	_err := _impl._router.Primary(ctx).
+		QueryRowContext(ctx, _sql, _args...).Scan(&_id)
+	if _err != nil {
+		return nil, _err
+	}
+	user.Id = _id
+	return user, nil
+}
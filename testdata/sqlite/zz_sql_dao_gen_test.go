@@ -0,0 +1,507 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"github.com/gomelon/melon/data"
+	"github.com/gomelon/melon/data/query"
+	"github.com/gomelon/sqlmap/dao"
+	"github.com/stretchr/testify/assert"
+	_ "modernc.org/sqlite"
+	"testing"
+	"time"
+)
+
+func TestCRUD(t *testing.T) {
+	a := assert.New(t)
+	tm, closeFunc := tm()
+	defer closeFunc()
+	var userDao UserDao = NewUserDaoSQLImpl(dao.NewSQLRouter(tm))
+	ctx := context.Background()
+	birthday := time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	//Insert
+	user, err := userDao.Insert(ctx, &User{Name: "Lucy", Gender: 2, Birthday: birthday, Email: "lucy@example.com",
+		Tags: Tags{"admin", "vip"}, Profile: &Profile{Nickname: "lucy", Hobbies: []string{"go"}},
+		CreatedAt: time.Now(), Remark: "not a column"})
+	a.Nil(err)
+	a.Equal(int64(1), user.Id)
+
+	users, err := userDao.InsertBatch(ctx, []*User{
+		{Name: "Lily", Gender: 2, Birthday: birthday, CreatedAt: time.Now()},
+		{Name: "Tom", Gender: 1, Birthday: birthday.AddDate(1, 0, 0), CreatedAt: time.Now()},
+	})
+	a.Nil(err)
+	a.Equal(int64(2), users[0].Id)
+	a.Equal(int64(3), users[1].Id)
+
+	//Find
+	found, err := userDao.FindById(ctx, user.Id)
+	a.Nil(err)
+	a.Equal("Lucy", found.Name)
+	a.Equal(Gender(2), found.Gender)
+	a.True(birthday.Equal(found.Birthday))
+	a.Equal(Tags{"admin", "vip"}, found.Tags)
+	a.Equal(&Profile{Nickname: "lucy", Hobbies: []string{"go"}}, found.Profile)
+
+	_, err = userDao.Insert(ctx, &User{Name: "Bad", Tags: Tags{"a,b"}})
+	a.EqualError(err, "sql: converting argument $5 type: tag can not contain comma")
+
+	found, err = userDao.FindByEmail(ctx, "lucy@example.com")
+	a.Nil(err)
+	a.Equal(user.Id, found.Id)
+	a.Empty(found.Remark)
+
+	found, err = userDao.FindByMail2(ctx, "lucy@example.com")
+	a.Nil(err)
+	a.Equal("Lucy", found.Name)
+	a.Equal("lucy@example.com", found.Email)
+
+	_, err = tm.OriginTXOrDB(ctx).Exec("INSERT INTO address (user_id, phone, city) VALUES (?, ?, ?)",
+		user.Id, "10086", "Shanghai")
+	a.Nil(err)
+
+	detail, err := userDao.FindDetailById(ctx, user.Id)
+	a.Nil(err)
+	a.Equal(user.Id, detail.Id)
+	a.Equal("Lucy", detail.Name)
+	a.Equal("10086", detail.Address.Phone)
+	a.Equal("Shanghai", detail.Address.City)
+
+	details, err := userDao.FindDetails(ctx)
+	a.Nil(err)
+	a.Len(details, 1)
+	a.Equal(user.Id, details[0].Id)
+	a.Equal("Shanghai", details[0].Address.City)
+
+	details, err = userDao.FindDetailsWithCity(ctx)
+	a.Nil(err)
+	a.Len(details, 3)
+	a.Equal("Shanghai", details[0].Address.City)
+	a.Empty(details[1].Address.City)
+
+	details, err = userDao.FindDetailsWithAddress(ctx)
+	a.Nil(err)
+	a.Len(details, 3)
+	a.Equal("10086", details[0].Address.Phone)
+	a.Empty(details[2].Address.Phone)
+
+	gender, err := userDao.FindMaxGenderByName(ctx, "Tom")
+	a.Nil(err)
+	a.Equal(Gender(1), gender)
+
+	gender, err = userDao.FindMaxGenderByName(ctx, "Nobody")
+	a.Nil(err)
+	a.Equal(Gender(0), gender)
+
+	row, err := userDao.FindRowById(ctx, user.Id)
+	a.Nil(err)
+	a.Equal(map[string]any{"id": user.Id, "name": "Lucy", "gender": int64(2)}, row)
+
+	row, err = userDao.FindRowById(ctx, 100)
+	a.Nil(err)
+	a.Nil(row)
+
+	rows, err := userDao.CountGroupByGender(ctx)
+	a.Nil(err)
+	a.Equal([]map[string]any{{"gender": int64(1), "total": int64(1)}, {"gender": int64(2), "total": int64(2)}}, rows)
+
+	femaleUsers, err := userDao.FindByGender(ctx, 2)
+	a.Nil(err)
+	a.Len(femaleUsers, 2)
+	a.Equal("Lily", femaleUsers[users[0].Id].Name)
+
+	detailsByPhone, err := userDao.FindDetailsByName(ctx, "Lucy")
+	a.Nil(err)
+	a.Equal(user.Id, detailsByPhone["10086"].Id)
+
+	//Page
+	for _, tt := range []struct {
+		page      int
+		wantNames []string
+	}{
+		{page: 1, wantNames: []string{"Lucy", "Lily"}},
+		{page: 2, wantNames: []string{"Tom"}},
+		{page: 3, wantNames: nil},
+	} {
+		page, err := userDao.FindByBirthdayGTEOrderById(ctx, birthday, query.NewPageRequest(tt.page, 2, true))
+		a.Nil(err)
+		a.Equal(int64(3), page.Total)
+		a.Equal(int64(2), page.TotalPages())
+		var names []string
+		for _, item := range page.Items {
+			names = append(names, item.Name)
+		}
+		a.Equal(tt.wantNames, names)
+	}
+
+	slice, err := userDao.FindNamesByGender(ctx, 2, query.NewPageRequest(1, 1, false))
+	a.Nil(err)
+	a.Equal([]string{"Lucy"}, slice.Items)
+	a.True(slice.HasNext)
+
+	slice, err = userDao.FindNamesByGender(ctx, 2, query.NewPageRequest(2, 1, false))
+	a.Nil(err)
+	a.Equal([]string{"Lily"}, slice.Items)
+	a.False(slice.HasNext)
+
+	//Sort
+	sorted, err := userDao.FindByGenderOrderByName(ctx, 2, nil)
+	a.Nil(err)
+	a.Equal("Lily", sorted[0].Name)
+
+	sorted, err = userDao.FindByGenderOrderByName(ctx, 2, []*query.Sort{query.NewSort("Id", query.DirectionDesc)})
+	a.Nil(err)
+	a.Equal("Lily", sorted[0].Name)
+
+	sorted, err = userDao.FindByGenderOrderByName(ctx, 2, []*query.Sort{query.NewSort("mail", query.DirectionDesc)})
+	a.Nil(err)
+	a.Equal("Lucy", sorted[0].Name)
+
+	_, err = userDao.FindByGenderOrderByName(ctx, 2, []*query.Sort{query.NewSort("id; drop table user", "")})
+	a.ErrorIs(err, dao.ErrUnsortable)
+
+	sortedDetails, err := userDao.FindDetailsSorted(ctx, []*query.Sort{
+		query.NewSort("Address.City", query.DirectionDesc), query.NewSort("id", query.DirectionAsc)})
+	a.Nil(err)
+	a.Len(sortedDetails, 3)
+	a.Equal("Lucy", sortedDetails[0].Name)
+
+	sortedPage, err := userDao.FindByBirthdayGTEOrderByName(ctx, birthday, query.NewPageRequest(1, 2, true),
+		[]*query.Sort{query.NewSort("birthday", query.DirectionDesc), query.NewSort("Id", query.DirectionAsc)})
+	a.Nil(err)
+	a.Equal(int64(3), sortedPage.Total)
+	a.Equal("Tom", sortedPage.Items[0].Name)
+	a.Equal("Lucy", sortedPage.Items[1].Name)
+
+	//Cursor
+	var cursorNames []string
+	cursor := dao.CursorRequest{Limit: 1}
+	for i := 0; i < 3; i++ {
+		cursorPage, err := userDao.QueryByGender(ctx, 2, cursor)
+		a.Nil(err)
+		for _, item := range cursorPage.Items {
+			cursorNames = append(cursorNames, item.Name)
+		}
+		if !cursorPage.HasNext() {
+			break
+		}
+		cursor.After = cursorPage.Next
+	}
+	a.Equal([]string{"Lily", "Lucy"}, cursorNames)
+
+	detailPage, err := userDao.FindDetailsAfter(ctx, birthday, &dao.CursorRequest{Limit: 2})
+	a.Nil(err)
+	a.Len(detailPage.Items, 2)
+	a.Equal(user.Id, detailPage.Items[0].Id)
+	a.Equal("Shanghai", detailPage.Items[0].Address.City)
+	a.True(detailPage.HasNext())
+
+	detailPage, err = userDao.FindDetailsAfter(ctx, birthday, &dao.CursorRequest{After: detailPage.Next, Limit: 2})
+	a.Nil(err)
+	a.Len(detailPage.Items, 1)
+	a.Equal("Tom", detailPage.Items[0].Name)
+	a.False(detailPage.HasNext())
+
+	namePage, err := userDao.FindNamesAfter(ctx, dao.CursorRequest{Limit: 2})
+	a.Nil(err)
+	a.Len(namePage.Items, 2)
+	a.Equal("Lily", namePage.Items[0].Name)
+	a.Equal("Lucy", namePage.Items[1].Name)
+
+	namePage, err = userDao.FindNamesAfter(ctx, dao.CursorRequest{After: namePage.Next, Limit: 2})
+	a.Nil(err)
+	a.Len(namePage.Items, 1)
+	a.Equal("Tom", namePage.Items[0].Name)
+	a.Empty(namePage.Next)
+
+	idPage, err := userDao.FindIdsAfter(ctx, dao.CursorRequest{Limit: 2})
+	a.Nil(err)
+	a.Len(idPage.Items, 2)
+	a.True(idPage.HasNext())
+
+	idPage, err = userDao.FindIdsAfter(ctx, dao.CursorRequest{After: idPage.Next, Limit: 2})
+	a.Nil(err)
+	a.Len(idPage.Items, 1)
+	a.Empty(idPage.Next)
+
+	_, err = userDao.FindIdsAfter(ctx, dao.CursorRequest{After: "bad", Limit: 2})
+	a.ErrorIs(err, dao.ErrInvalidCursor)
+
+	_, err = userDao.FindIdsAfter(ctx, dao.CursorRequest{})
+	a.ErrorIs(err, dao.ErrInvalidLimit)
+
+	//Stream
+	femaleRows, err := userDao.QueryByGenderOrderById(ctx, 2)
+	a.Nil(err)
+	var femaleNames []string
+	for femaleRows.Next() {
+		femaleNames = append(femaleNames, femaleRows.Item().Name)
+	}
+	a.Nil(femaleRows.Err())
+	a.Equal([]string{"Lucy", "Lily"}, femaleNames)
+
+	//stop early, the connection must be released by Close
+	nameRows, err := userDao.FindNames(ctx)
+	a.Nil(err)
+	a.True(nameRows.Next())
+	a.Equal("Lucy", nameRows.Item())
+	a.Nil(nameRows.Close())
+
+	cancelCtx, cancel := context.WithCancel(ctx)
+	nameRows, err = userDao.FindNames(cancelCtx)
+	a.Nil(err)
+	a.True(nameRows.Next())
+	cancel()
+	a.False(nameRows.Next())
+	a.ErrorIs(nameRows.Err(), context.Canceled)
+
+	var streamed []string
+	err = userDao.FindByBirthdayLT(ctx, birthday.AddDate(2, 0, 0), func(user *User) error {
+		streamed = append(streamed, user.Name)
+		if len(streamed) == 2 {
+			return dao.Break
+		}
+		return nil
+	})
+	a.Nil(err)
+	a.Equal([]string{"Lucy", "Lily"}, streamed)
+
+	stopErr := errors.New("stop")
+	err = userDao.FindDetailsByGender(ctx, 2, func(detail UserDetail) error {
+		a.Equal("Shanghai", detail.Address.City)
+		return stopErr
+	})
+	a.Equal(stopErr, err)
+
+	found, err = userDao.FindById(ctx, 100)
+	a.Nil(err)
+	a.Nil(found)
+
+	founds, err := userDao.FindByBirthdayGTE(ctx, birthday)
+	a.Nil(err)
+	a.Len(founds, 3)
+
+	founds, err = userDao.FindByNameContains(ctx, "L")
+	a.Nil(err)
+	a.Len(founds, 2)
+
+	exists, err := userDao.ExistsById(ctx, users[1].Id)
+	a.Nil(err)
+	a.True(exists)
+
+	count, err := userDao.CountByBirthdayGTE(ctx, birthday.AddDate(1, 0, 0))
+	a.Nil(err)
+	a.Equal(1, count)
+
+	//Dynamic
+	founds, err = userDao.Search(ctx, "", 0, time.Time{})
+	a.Nil(err)
+	a.Len(founds, 3)
+
+	founds, err = userDao.Search(ctx, "L%", 2, time.Time{})
+	a.Nil(err)
+	a.Len(founds, 2)
+	a.Equal("Lucy", founds[0].Name)
+
+	founds, err = userDao.Search(ctx, "", 0, birthday.AddDate(1, 0, 0))
+	a.Nil(err)
+	a.Len(founds, 1)
+	a.Equal("Tom", founds[0].Name)
+
+	//In
+	founds, err = userDao.FindByIdIn(ctx, []int64{user.Id, users[1].Id})
+	a.Nil(err)
+	a.Len(founds, 2)
+
+	founds, err = userDao.FindByIdIn(ctx, nil)
+	a.Nil(err)
+	a.Empty(founds)
+
+	names, err := userDao.FindNamesByGenders(ctx, []Gender{1, 2}, "Lucy")
+	a.Nil(err)
+	a.Equal([]string{"Lily", "Tom"}, names)
+
+	names, err = userDao.FindNamesByIdNotIn(ctx, []int64{user.Id})
+	a.Nil(err)
+	a.NotContains(names, user.Name)
+	a.Len(names, 2)
+
+	names, err = userDao.FindNamesByIdNotIn(ctx, nil)
+	a.Nil(err)
+	a.Len(names, 3)
+
+	//Struct Param
+	founds, err = userDao.FindByFilter(ctx, &UserFilter{Genders: []Gender{2}})
+	a.Nil(err)
+	a.Len(founds, 2)
+
+	founds, err = userDao.FindByFilter(ctx, &UserFilter{Name: "T%", Birthday: birthday})
+	a.Nil(err)
+	a.Len(founds, 1)
+	a.Equal("Tom", founds[0].Name)
+
+	count, err = userDao.CountByFilter(ctx, UserFilter{Birthday: birthday, Address: Address{City: "Shanghai"}})
+	a.Nil(err)
+	a.Equal(1, count)
+
+	founds, err = userDao.FindByKeyword(ctx, 2, "lucy@example.com")
+	a.Nil(err)
+	a.Len(founds, 1)
+	a.Equal("Lucy", founds[0].Name)
+
+	//Update
+	rowsAffected, err := userDao.UpdateNameAndGenderById(ctx, "Jerry", 1, users[1].Id)
+	a.Nil(err)
+	a.Equal(int64(1), rowsAffected)
+
+	//the column of Email is mail by its db tag
+	rowsAffected, err = userDao.UpdateEmailById(ctx, "jerry@example.com", users[1].Id)
+	a.Nil(err)
+	a.Equal(int64(1), rowsAffected)
+	found, err = userDao.FindById(ctx, users[1].Id)
+	a.Nil(err)
+	a.Equal("jerry@example.com", found.Email)
+
+	user.Name = "Lucy2"
+	user.Profile.Nickname = "lucy2"
+	rowsAffected, err = userDao.UpdateById(ctx, user.Id, user)
+	a.Nil(err)
+	a.Equal(int64(1), rowsAffected)
+
+	found, err = userDao.FindById(ctx, users[1].Id)
+	a.Nil(err)
+	a.Equal("Jerry", found.Name)
+	a.Nil(found.Tags)
+	a.Nil(found.Profile)
+
+	rowsAffected, err = userDao.UpdateSelective(ctx, users[0].Id, &User{Email: "lily@example.com"})
+	a.Nil(err)
+	a.Equal(int64(1), rowsAffected)
+
+	found, err = userDao.FindById(ctx, users[0].Id)
+	a.Nil(err)
+	a.Equal("Lily", found.Name)
+	a.Equal("lily@example.com", found.Email)
+
+	//Upsert
+	upserted, err := userDao.Upsert(ctx, &User{Id: user.Id, Name: "Lucy3", Gender: 1, Birthday: birthday})
+	a.Nil(err)
+	a.Equal(user.Id, upserted.Id)
+
+	found, err = userDao.FindById(ctx, user.Id)
+	a.Nil(err)
+	a.Equal("Lucy3", found.Name)
+	a.Equal("lucy2", found.Profile.Nickname)
+	a.Equal(Gender(1), found.Gender)
+
+	//the zero id of a new entity is not inserted, so the new entities do not conflict
+	jack, err := userDao.Upsert(ctx, &User{Name: "Jack", Gender: 1, Birthday: birthday})
+	a.Nil(err)
+	a.NotZero(jack.Id)
+
+	rose, err := userDao.Upsert(ctx, &User{Name: "Rose", Gender: 2, Birthday: birthday})
+	a.Nil(err)
+	a.NotEqual(jack.Id, rose.Id)
+
+	found, err = userDao.FindById(ctx, jack.Id)
+	a.Nil(err)
+	a.Equal("Jack", found.Name)
+
+	//Delete
+	for _, id := range []int64{user.Id, users[0].Id, users[1].Id, jack.Id, rose.Id} {
+		rowsAffected, err = userDao.DeleteById(ctx, id)
+		a.Nil(err)
+		a.Equal(int64(1), rowsAffected)
+	}
+
+	exists, err = userDao.ExistsById(ctx, user.Id)
+	a.Nil(err)
+	a.False(exists)
+}
+
+func TestInsertResult(t *testing.T) {
+	a := assert.New(t)
+	tm, closeFunc := tm()
+	defer closeFunc()
+	var userDao UserDao = NewUserDaoSQLImpl(dao.NewSQLRouter(tm))
+	ctx := context.Background()
+
+	//the int64 result is the rows affected unless LastInsertId is declared, whatever its name is
+	for i := 0; i < 2; i++ {
+		affected, err := userDao.InsertCount(ctx, &User{Name: "Lucy"})
+		a.Nil(err)
+		a.Equal(int64(1), affected)
+	}
+
+	id, err := userDao.InsertForId(ctx, &User{Name: "Lily"})
+	a.Nil(err)
+	a.Equal(int64(3), id)
+
+	//the ids of a batch insert are not filled unless ConsecutiveIds is declared
+	users, err := userDao.SaveBatch(ctx, []*User{{Name: "Tom"}, {Name: "Jerry"}})
+	a.Nil(err)
+	a.Equal(int64(0), users[0].Id)
+	a.Equal(int64(0), users[1].Id)
+}
+
+func TestOmitempty(t *testing.T) {
+	a := assert.New(t)
+	tm, closeFunc := tm()
+	defer closeFunc()
+	var userDao UserDao = NewUserDaoSQLImpl(dao.NewSQLRouter(tm))
+	ctx := context.Background()
+
+	//the zero CreatedAt is not inserted, so the column keeps its default
+	user, err := userDao.Insert(ctx, &User{Name: "Lucy"})
+	a.Nil(err)
+	found, err := userDao.FindById(ctx, user.Id)
+	a.Nil(err)
+	a.False(found.CreatedAt.IsZero())
+
+	createdAt := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	user, err = userDao.Insert(ctx, &User{Name: "Lily", CreatedAt: createdAt})
+	a.Nil(err)
+	found, err = userDao.FindById(ctx, user.Id)
+	a.Nil(err)
+	a.True(createdAt.Equal(found.CreatedAt))
+
+	//the zero CreatedAt is not updated, so the column keeps its value
+	rowsAffected, err := userDao.UpdateById(ctx, user.Id, &User{Name: "Lily2"})
+	a.Nil(err)
+	a.Equal(int64(1), rowsAffected)
+	found, err = userDao.FindById(ctx, user.Id)
+	a.Nil(err)
+	a.Equal("Lily2", found.Name)
+	a.True(createdAt.Equal(found.CreatedAt))
+}
+
+func tm() (tm *data.SQLTXManager, closeFunc func()) {
+	db, err := sql.Open("sqlite", ":memory:")
+	if err != nil {
+		panic(err)
+	}
+	//every connection of :memory: has its own database
+	db.SetMaxOpenConns(1)
+	_, err = db.Exec("CREATE TABLE user (id INTEGER PRIMARY KEY AUTOINCREMENT, name TEXT NOT NULL, " +
+		"gender INTEGER NOT NULL, birthday DATETIME, mail TEXT, tags TEXT NOT NULL DEFAULT '', profile TEXT, " +
+		"created_at DATETIME DEFAULT CURRENT_TIMESTAMP)")
+	if err != nil {
+		panic(err)
+	}
+	_, err = db.Exec("CREATE TABLE address (id INTEGER PRIMARY KEY AUTOINCREMENT, user_id INTEGER NOT NULL, " +
+		"phone TEXT NOT NULL, city TEXT NOT NULL)")
+	if err != nil {
+		panic(err)
+	}
+
+	tm = data.NewSqlTxManager("user", db)
+	closeFunc = func() {
+		err := db.Close()
+		if err != nil {
+			panic(err)
+		}
+	}
+	return
+}
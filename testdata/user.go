@@ -14,7 +14,7 @@ type User struct {
 	Name      string
 	Gender    Gender
 	Birthday  time.Time
-	CreatedAt time.Time
+	CreatedAt time.Time `db:",omitempty"`
 }
 
 //UserDao
@@ -44,14 +44,30 @@ type UserDao interface {
 	/*+sqlmap.Select Query="select count(*) as count from `user` where birthday >= :time"*/
 	CountByBirthdayGTE2(ctx context.Context, time time.Time) (int, error)
 
-	//Insert
-	//+sqlmap.None
 	Insert(ctx context.Context, user *User) (*User, error)
 
-	//UpdateById
-	//+sqlmap.None
+	//Insert2
+	/*+sqlmap.Insert Query="insert into `user`(name, gender, birthday) values(:name, :gender, :birthday)"*/
+	Insert2(ctx context.Context, user *User) (*User, error)
+
+	//InsertBatch
+	/*+sqlmap.Insert ConsecutiveIds=true*/
+	InsertBatch(ctx context.Context, users []*User) (int64, error)
+
+	//Upsert
+	/*+sqlmap.Upsert ConflictKeys="id" UpdateColumns="name,gender"*/
+	Upsert(ctx context.Context, user *User) (*User, error)
+
 	UpdateById(ctx context.Context, id int64, user *User) (int64, error)
 
+	UpdateNameAndGenderById(ctx context.Context, name string, gender Gender, id int64) (int64, error)
+
+	UpdateBirthdayById(ctx context.Context, id int64, user *User) (int64, error)
+
+	//UpdateNameById2
+	/*+sqlmap.Update Query="update `user` set name = :name where id = :id"*/
+	UpdateNameById2(ctx context.Context, name string, id int64) (int64, error)
+
 	DeleteById(ctx context.Context, id int64) (int64, error)
 
 	//DeleteById2
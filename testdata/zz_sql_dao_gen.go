@@ -4,31 +4,33 @@ package testdata
 
 import (
 	"context"
+	"strings"
 	"time"
 
-	"github.com/gomelon/melon/data"
+	"github.com/gomelon/sqlmap/dao"
 )
 
 var _ UserDao = &UserDaoSQLImpl{}
 
 //meta:data source=UserDao tags=mysql,dao,struct
 type UserDaoSQLImpl struct {
-	_tm *data.SQLTXManager
+	_router dao.Router
 }
 
-//NewUserDaoSQLImpl UserDaoSQLImpl provider
-//+autowire.Provider
+// NewUserDaoSQLImpl UserDaoSQLImpl provider
+// +autowire.Provider
+//
 //meta:data source=UserDao tags=mysql,dao,provider
-func NewUserDaoSQLImpl(_tm *data.SQLTXManager) *UserDaoSQLImpl {
+func NewUserDaoSQLImpl(_router dao.Router) *UserDaoSQLImpl {
 	return &UserDaoSQLImpl{
-		_tm: _tm,
+		_router: _router,
 	}
 }
 
 func (_impl *UserDaoSQLImpl) CountByBirthdayGTE(ctx context.Context, time time.Time) (int, error) {
 	_sql := "SELECT COUNT(*) AS X FROM `user` WHERE (`birthday` >= ?)"
-	_rows, _err := _impl._tm.OriginTXOrDB(ctx).
-		Query(_sql, time)
+	_rows, _err := _impl._router.Replica(ctx).
+		QueryContext(ctx, _sql, time)
 
 	var _item int
 	if _err != nil {
@@ -41,15 +43,14 @@ func (_impl *UserDaoSQLImpl) CountByBirthdayGTE(ctx context.Context, time time.T
 		return _item, _rows.Err()
 	}
 
-	_item = int(0)
 	_err = _rows.Scan(&_item)
 	return _item, _err
 }
 
 func (_impl *UserDaoSQLImpl) CountByBirthdayGTE2(ctx context.Context, time time.Time) (int, error) {
 	_sql := "select count(*) as count from `user` where birthday >= ?"
-	_rows, _err := _impl._tm.OriginTXOrDB(ctx).
-		Query(_sql, time)
+	_rows, _err := _impl._router.Replica(ctx).
+		QueryContext(ctx, _sql, time)
 
 	var _item int
 	if _err != nil {
@@ -62,15 +63,14 @@ func (_impl *UserDaoSQLImpl) CountByBirthdayGTE2(ctx context.Context, time time.
 		return _item, _rows.Err()
 	}
 
-	_item = int(0)
 	_err = _rows.Scan(&_item)
 	return _item, _err
 }
 
 func (_impl *UserDaoSQLImpl) DeleteById(ctx context.Context, id int64) (int64, error) {
 	_sql := "DELETE FROM `user` WHERE (`id` = ?)"
-	_result, err := _impl._tm.OriginTXOrDB(ctx).
-		Exec(_sql, id)
+	_result, err := _impl._router.Primary(ctx).
+		ExecContext(ctx, _sql, id)
 	if err != nil {
 		return 0, err
 	}
@@ -79,8 +79,8 @@ func (_impl *UserDaoSQLImpl) DeleteById(ctx context.Context, id int64) (int64, e
 
 func (_impl *UserDaoSQLImpl) DeleteById2(ctx context.Context, id int64) (int64, error) {
 	_sql := "delete from `user` where id = ?"
-	_result, err := _impl._tm.OriginTXOrDB(ctx).
-		Exec(_sql, id)
+	_result, err := _impl._router.Primary(ctx).
+		ExecContext(ctx, _sql, id)
 	if err != nil {
 		return 0, err
 	}
@@ -89,8 +89,8 @@ func (_impl *UserDaoSQLImpl) DeleteById2(ctx context.Context, id int64) (int64,
 
 func (_impl *UserDaoSQLImpl) ExistsById(ctx context.Context, id int64) (bool, error) {
 	_sql := "SELECT 1 AS X FROM `user` WHERE (`id` = ?) LIMIT 0, 1"
-	_rows, _err := _impl._tm.OriginTXOrDB(ctx).
-		Query(_sql, id)
+	_rows, _err := _impl._router.Replica(ctx).
+		QueryContext(ctx, _sql, id)
 
 	var _item bool
 	if _err != nil {
@@ -103,15 +103,14 @@ func (_impl *UserDaoSQLImpl) ExistsById(ctx context.Context, id int64) (bool, er
 		return _item, _rows.Err()
 	}
 
-	_item = false
 	_err = _rows.Scan(&_item)
 	return _item, _err
 }
 
 func (_impl *UserDaoSQLImpl) ExistsById2(ctx context.Context, id int64) (bool, error) {
 	_sql := "select 1 as X from `user` WHERE id = ? limit 1"
-	_rows, _err := _impl._tm.OriginTXOrDB(ctx).
-		Query(_sql, id)
+	_rows, _err := _impl._router.Replica(ctx).
+		QueryContext(ctx, _sql, id)
 
 	var _item bool
 	if _err != nil {
@@ -124,15 +123,14 @@ func (_impl *UserDaoSQLImpl) ExistsById2(ctx context.Context, id int64) (bool, e
 		return _item, _rows.Err()
 	}
 
-	_item = false
 	_err = _rows.Scan(&_item)
 	return _item, _err
 }
 
 func (_impl *UserDaoSQLImpl) FindByBirthdayGTE(ctx context.Context, time time.Time) ([]*User, error) {
 	_sql := "SELECT id, name, gender, birthday, created_at FROM `user` WHERE (`birthday` >= ?)"
-	_rows, _err := _impl._tm.OriginTXOrDB(ctx).
-		Query(_sql, time)
+	_rows, _err := _impl._router.Replica(ctx).
+		QueryContext(ctx, _sql, time)
 
 	var _items []*User
 	if _err != nil {
@@ -141,10 +139,6 @@ func (_impl *UserDaoSQLImpl) FindByBirthdayGTE(ctx context.Context, time time.Ti
 
 	defer _rows.Close()
 
-	if !_rows.Next() {
-		return _items, _rows.Err()
-	}
-
 	for _rows.Next() {
 		_item := &User{}
 		_err = _rows.Scan(&_item.Id, &_item.Name, &_item.Gender, &_item.Birthday, &_item.CreatedAt)
@@ -153,13 +147,13 @@ func (_impl *UserDaoSQLImpl) FindByBirthdayGTE(ctx context.Context, time time.Ti
 		}
 		_items = append(_items, _item)
 	}
-	return _items, nil
+	return _items, _rows.Err()
 }
 
 func (_impl *UserDaoSQLImpl) FindByBirthdayGTE2(ctx context.Context, time time.Time) ([]*User, error) {
 	_sql := "select id, name, gender, birthday, created_at from `user` where birthday >= ?"
-	_rows, _err := _impl._tm.OriginTXOrDB(ctx).
-		Query(_sql, time)
+	_rows, _err := _impl._router.Replica(ctx).
+		QueryContext(ctx, _sql, time)
 
 	var _items []*User
 	if _err != nil {
@@ -168,10 +162,6 @@ func (_impl *UserDaoSQLImpl) FindByBirthdayGTE2(ctx context.Context, time time.T
 
 	defer _rows.Close()
 
-	if !_rows.Next() {
-		return _items, _rows.Err()
-	}
-
 	for _rows.Next() {
 		_item := &User{}
 		_err = _rows.Scan(&_item.Id, &_item.Name, &_item.Gender, &_item.Birthday, &_item.CreatedAt)
@@ -180,14 +170,13 @@ func (_impl *UserDaoSQLImpl) FindByBirthdayGTE2(ctx context.Context, time time.T
 		}
 		_items = append(_items, _item)
 	}
-	return _items, nil
+	return _items, _rows.Err()
 }
 
-func (_impl *UserDaoSQLImpl) FindById(ctx context.Context,
-	id int64) (*User, error) {
-	_sql := "SELECT id, name FROM `user` WHERE (`id` = ?)"
-	_rows, _err := _impl._tm.OriginTXOrDB(ctx).
-		Query(_sql, id)
+func (_impl *UserDaoSQLImpl) FindById(ctx context.Context, id int64) (*User, error) {
+	_sql := "SELECT id, name, gender, birthday, created_at FROM `user` WHERE (`id` = ?)"
+	_rows, _err := _impl._router.Replica(ctx).
+		QueryContext(ctx, _sql, id)
 
 	var _item *User
 	if _err != nil {
@@ -201,14 +190,14 @@ func (_impl *UserDaoSQLImpl) FindById(ctx context.Context,
 	}
 
 	_item = &User{}
-	_err = _rows.Scan(&_item.Id, &_item.Name)
+	_err = _rows.Scan(&_item.Id, &_item.Name, &_item.Gender, &_item.Birthday, &_item.CreatedAt)
 	return _item, _err
 }
 
 func (_impl *UserDaoSQLImpl) FindById2(ctx context.Context, id int64) (*User, error) {
 	_sql := "select id, name, gender, birthday, created_at from `user` where id = ?"
-	_rows, _err := _impl._tm.OriginTXOrDB(ctx).
-		Query(_sql, id)
+	_rows, _err := _impl._router.Primary(ctx).
+		QueryContext(ctx, _sql, id)
 
 	var _item *User
 	if _err != nil {
@@ -225,3 +214,162 @@ func (_impl *UserDaoSQLImpl) FindById2(ctx context.Context, id int64) (*User, er
 	_err = _rows.Scan(&_item.Id, &_item.Name, &_item.Gender, &_item.Birthday, &_item.CreatedAt)
 	return _item, _err
 }
+
+func (_impl *UserDaoSQLImpl) Insert(ctx context.Context, user *User) (*User, error) {
+	_builder := dao.NewSQLBuilder(dao.BindQuestion)
+	_builder.Write("INSERT INTO `user` (`name`, `gender`, `birthday`")
+	if !user.CreatedAt.IsZero() {
+		_builder.Write(", `created_at`")
+	}
+	_builder.Write(") VALUES (")
+	_builder.Arg(user.Name)
+	_builder.Write(", ")
+	_builder.Arg(user.Gender)
+	_builder.Write(", ")
+	_builder.Arg(user.Birthday)
+	if !user.CreatedAt.IsZero() {
+		_builder.Write(", ")
+		_builder.Arg(user.CreatedAt)
+	}
+	_builder.Write(")")
+	_sql, _args := _builder.SQL(), _builder.Args()
+	_result, _err := _impl._router.Primary(ctx).
+		ExecContext(ctx, _sql, _args...)
+	if _err != nil {
+		return nil, _err
+	}
+	_id, _err := _result.LastInsertId()
+	if _err != nil {
+		return nil, _err
+	}
+	user.Id = _id
+	return user, nil
+}
+
+func (_impl *UserDaoSQLImpl) Insert2(ctx context.Context, user *User) (*User, error) {
+	_sql := "insert into `user`(name, gender, birthday) values(?, ?, ?)"
+	_result, _err := _impl._router.Primary(ctx).
+		ExecContext(ctx, _sql, user.Name, user.Gender, user.Birthday)
+	if _err != nil {
+		return nil, _err
+	}
+	_id, _err := _result.LastInsertId()
+	if _err != nil {
+		return nil, _err
+	}
+	user.Id = _id
+	return user, nil
+}
+
+func (_impl *UserDaoSQLImpl) InsertBatch(ctx context.Context, users []*User) (int64, error) {
+	_db := _impl._router.Primary(ctx)
+	var _rowsAffected int64
+	for _start := 0; _start < len(users); _start += 16383 {
+		_end := _start + 16383
+		if _end > len(users) {
+			_end = len(users)
+		}
+		_sqlBuilder := strings.Builder{}
+		_sqlBuilder.WriteString("INSERT INTO `user` (`name`, `gender`, `birthday`, `created_at`) VALUES ")
+		_args := make([]any, 0, (_end-_start)*4)
+		for _i, _item := range users[_start:_end] {
+			if _i > 0 {
+				_sqlBuilder.WriteString(", ")
+			}
+			_sqlBuilder.WriteString("(?, ?, ?, ?)")
+			_args = append(_args, _item.Name, _item.Gender, _item.Birthday, _item.CreatedAt)
+		}
+		_result, _err := _db.ExecContext(ctx, _sqlBuilder.String(), _args...)
+		if _err != nil {
+			return _rowsAffected, _err
+		}
+		_affected, _err := _result.RowsAffected()
+		if _err != nil {
+			return _rowsAffected, _err
+		}
+		_rowsAffected += _affected
+		_id, _err := _result.LastInsertId()
+		if _err != nil {
+			return _rowsAffected, _err
+		}
+		for _i := _start; _i < _end; _i++ {
+			users[_i].Id = _id + int64(_i-_start)
+		}
+	}
+	return _rowsAffected, nil
+}
+
+func (_impl *UserDaoSQLImpl) UpdateBirthdayById(ctx context.Context, id int64, user *User) (int64, error) {
+	_sql := "UPDATE `user` SET `birthday` = ? WHERE (`id` = ?)"
+	_result, _err := _impl._router.Primary(ctx).
+		ExecContext(ctx, _sql, user.Birthday, id)
+	if _err != nil {
+		return 0, _err
+	}
+	return _result.RowsAffected()
+}
+
+func (_impl *UserDaoSQLImpl) UpdateById(ctx context.Context, id int64, user *User) (int64, error) {
+	_builder := dao.NewSQLBuilder(dao.BindQuestion)
+	_builder.Write("UPDATE `user` ")
+	_builder.Begin(dao.ClauseSet)
+	_builder.Write(" `name` = ")
+	_builder.Arg(user.Name)
+	_builder.Write(", `gender` = ")
+	_builder.Arg(user.Gender)
+	_builder.Write(", `birthday` = ")
+	_builder.Arg(user.Birthday)
+	_builder.Write(", ")
+	if !user.CreatedAt.IsZero() {
+		_builder.Write(" `created_at` = ")
+		_builder.Arg(user.CreatedAt)
+		_builder.Write(", ")
+	}
+	_builder.Write(" ")
+	_builder.End()
+	_builder.Write(" WHERE (`id` = ")
+	_builder.Arg(id)
+	_builder.Write(")")
+	_sql, _args := _builder.SQL(), _builder.Args()
+	_result, _err := _impl._router.Primary(ctx).
+		ExecContext(ctx, _sql, _args...)
+	if _err != nil {
+		return 0, _err
+	}
+	return _result.RowsAffected()
+}
+
+func (_impl *UserDaoSQLImpl) UpdateNameAndGenderById(ctx context.Context, name string, gender Gender, id int64) (int64, error) {
+	_sql := "UPDATE `user` SET `name` = ?, `gender` = ? WHERE (`id` = ?)"
+	_result, _err := _impl._router.Primary(ctx).
+		ExecContext(ctx, _sql, name, gender, id)
+	if _err != nil {
+		return 0, _err
+	}
+	return _result.RowsAffected()
+}
+
+func (_impl *UserDaoSQLImpl) UpdateNameById2(ctx context.Context, name string, id int64) (int64, error) {
+	_sql := "update `user` set name = ? where id = ?"
+	_result, _err := _impl._router.Primary(ctx).
+		ExecContext(ctx, _sql, name, id)
+	if _err != nil {
+		return 0, _err
+	}
+	return _result.RowsAffected()
+}
+
+func (_impl *UserDaoSQLImpl) Upsert(ctx context.Context, user *User) (*User, error) {
+	_sql := "INSERT INTO `user` (`id`, `name`, `gender`, `birthday`, `created_at`) VALUES (?, ?, ?, ?, ?) ON DUPLICATE KEY UPDATE `id` = LAST_INSERT_ID(`id`), `name` = VALUES(`name`), `gender` = VALUES(`gender`)"
+	_result, _err := _impl._router.Primary(ctx).
+		ExecContext(ctx, _sql, user.Id, user.Name, user.Gender, user.Birthday, user.CreatedAt)
+	if _err != nil {
+		return nil, _err
+	}
+	_id, _err := _result.LastInsertId()
+	if _err != nil {
+		return nil, _err
+	}
+	user.Id = _id
+	return user, nil
+}
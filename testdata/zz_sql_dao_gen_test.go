@@ -6,6 +6,7 @@ import (
 	"fmt"
 	_ "github.com/go-sql-driver/mysql"
 	"github.com/gomelon/melon/data"
+	"github.com/gomelon/sqlmap/dao"
 	"github.com/stretchr/testify/assert"
 	"math"
 	"testing"
@@ -17,7 +18,7 @@ func TestCRUD(t *testing.T) {
 	// prepare
 	tm, closeFunc := tm()
 	defer closeFunc()
-	var userDao UserDao = NewUserDaoSQLImpl(tm)
+	var userDao UserDao = NewUserDaoSQLImpl(dao.NewSQLRouter(tm))
 
 	// execute
 	var err error
@@ -39,6 +40,25 @@ func TestCRUD(t *testing.T) {
 	a.NoError(err, "Insert fail")
 	a.Greater(user1.Id, int64(0), "insert then get id fail")
 
+	user3 := &User{
+		Name:     "GoMelon3",
+		Gender:   0,
+		Birthday: time.Now(),
+	}
+	user3, err = userDao.Insert2(ctx, user3)
+	a.NoError(err, "Insert2 fail")
+	a.Greater(user3.Id, int64(0), "Insert2 then get id fail")
+
+	batchUsers := []*User{
+		{Name: "GoMelonBatch1", Birthday: time.Now()},
+		{Name: "GoMelonBatch2", Birthday: time.Now()},
+	}
+	insertCount, err := userDao.InsertBatch(ctx, batchUsers)
+	a.NoError(err, "InsertBatch fail")
+	a.Equal(insertCount, int64(2), "InsertBatch fail")
+	a.Greater(batchUsers[0].Id, int64(0), "InsertBatch then get id fail")
+	a.Equal(batchUsers[0].Id+1, batchUsers[1].Id, "InsertBatch then get id fail")
+
 	// -------------------------- Find Single Start --------------------------
 
 	foundUser, err := userDao.FindById(ctx, user1.Id)
@@ -123,8 +143,57 @@ func TestCRUD(t *testing.T) {
 
 	// -------------------------- Count End   --------------------------
 
+	// -------------------------- Update Start --------------------------
+	user1.Name = "GoMelon1Updated"
+	updateCount, err := userDao.UpdateById(ctx, user1.Id, user1)
+	a.NoError(err, "UpdateById fail")
+	a.Equal(updateCount, int64(1), "UpdateById fail")
+
+	foundUser, err = userDao.FindById(ctx, user1.Id)
+	a.NoError(err, "FindById fail")
+	a.Equal(foundUser.Name, user1.Name, "UpdateById fail")
+
+	updateCount, err = userDao.UpdateNameAndGenderById(ctx, "GoMelon1Partial", 1, user1.Id)
+	a.NoError(err, "UpdateNameAndGenderById fail")
+	a.Equal(updateCount, int64(1), "UpdateNameAndGenderById fail")
+
+	user1.Birthday = time.Now().Add(-2 * time.Hour)
+	updateCount, err = userDao.UpdateBirthdayById(ctx, user1.Id, user1)
+	a.NoError(err, "UpdateBirthdayById fail")
+	a.Equal(updateCount, int64(1), "UpdateBirthdayById fail")
+
+	updateCount, err = userDao.UpdateNameById2(ctx, "GoMelon2Updated", user2.Id)
+	a.NoError(err, "UpdateNameById2 fail")
+	a.Equal(updateCount, int64(1), "UpdateNameById2 fail")
+
+	updateCount, err = userDao.UpdateNameById2(ctx, "GoMelon2Updated", math.MaxInt64)
+	a.NoError(err, "UpdateNameById2 fail")
+	a.Equal(updateCount, int64(0), "UpdateNameById2 fail")
+	// -------------------------- Update End   --------------------------
+
+	// -------------------------- Upsert Start --------------------------
+	upsertUser := &User{Name: "GoMelonUpsert", Birthday: time.Now()}
+	upsertUser, err = userDao.Upsert(ctx, upsertUser)
+	a.NoError(err, "Upsert fail")
+	a.Greater(upsertUser.Id, int64(0), "Upsert then get id fail")
+
+	upsertUserId := upsertUser.Id
+	upsertUser.Name = "GoMelonUpsertUpdated"
+	upsertUser, err = userDao.Upsert(ctx, upsertUser)
+	a.NoError(err, "Upsert fail")
+	a.Equal(upsertUser.Id, upsertUserId, "Upsert fail")
+
+	foundUser, err = userDao.FindById(ctx, upsertUserId)
+	a.NoError(err, "FindById fail")
+	a.Equal(foundUser.Name, "GoMelonUpsertUpdated", "Upsert fail")
+
+	deleteCount, err := userDao.DeleteById(ctx, upsertUserId)
+	a.NoError(err, "DeleteById fail")
+	a.Greater(deleteCount, int64(0), "DeleteById fail")
+	// -------------------------- Upsert End   --------------------------
+
 	// -------------------------- Delete Start --------------------------
-	deleteCount, err := userDao.DeleteById(ctx, user1.Id)
+	deleteCount, err = userDao.DeleteById(ctx, user1.Id)
 	a.NoError(err, "DeleteById fail")
 	a.Greater(deleteCount, int64(0), "DeleteById fail")
 
@@ -139,6 +208,16 @@ func TestCRUD(t *testing.T) {
 	deleteCount, err = userDao.DeleteById2(ctx, math.MaxInt64)
 	a.NoError(err, "DeleteById2 fail")
 	a.Equal(deleteCount, int64(0), "DeleteById2 fail")
+
+	deleteCount, err = userDao.DeleteById(ctx, user3.Id)
+	a.NoError(err, "DeleteById fail")
+	a.Greater(deleteCount, int64(0), "DeleteById fail")
+
+	for _, batchUser := range batchUsers {
+		deleteCount, err = userDao.DeleteById(ctx, batchUser.Id)
+		a.NoError(err, "DeleteById fail")
+		a.Greater(deleteCount, int64(0), "DeleteById fail")
+	}
 	// -------------------------- Delete End   --------------------------
 }
 
@@ -146,7 +225,7 @@ func TestTransactionRollback(t *testing.T) {
 	// prepare
 	tm, closeFunc := tm()
 	defer closeFunc()
-	var userDao UserDao = NewUserDaoSQLImpl(tm)
+	var userDao UserDao = NewUserDaoSQLImpl(dao.NewSQLRouter(tm))
 
 	// execute
 	ctx := context.Background()
@@ -196,24 +275,3 @@ func tm() (tm *data.SQLTXManager, closeFunc func()) {
 	}
 	return
 }
-
-func (_impl *UserDaoSQLImpl) Insert(ctx context.Context, user *User) (*User, error) {
-	query := "INSERT INTO `user`(`name`,`gender`,`birthday`)" +
-		"VALUES (?, ?, ?)"
-	db := _impl._tm.OriginTXOrDB(ctx)
-	result, err := db.Exec(query, user.Name, user.Gender, user.Birthday)
-	if err != nil {
-		return nil, err
-	}
-	id, err := result.LastInsertId()
-	user.Id = id
-	return user, err
-}
-
-func (_impl *UserDaoSQLImpl) UpdateById(ctx context.Context, id int64, user *User) (int64, error) {
-	query := "UPDATE `user` SET `name`= ?, `gender` = ?, `birthday` = ?, `created_at` = ? " +
-		"WHERE `id` = ?"
-	db := _impl._tm.OriginTXOrDB(ctx)
-	result, _ := db.Exec(query, user.Name, user.Gender, user.Birthday, id)
-	return result.RowsAffected()
-}